@@ -6,6 +6,7 @@ import (
 	"errors"
 	"flag"
 	"go/ast"
+	"path/filepath"
 	"strings"
 
 	"golang.org/x/tools/go/analysis"
@@ -13,21 +14,35 @@ import (
 	"golang.org/x/tools/go/ast/inspector"
 
 	"github.com/mpyw/goroutinectx/internal"
+	"github.com/mpyw/goroutinectx/internal/baseline"
 	"github.com/mpyw/goroutinectx/internal/checkers"
+	"github.com/mpyw/goroutinectx/internal/checkers/ctxfield"
+	"github.com/mpyw/goroutinectx/internal/checkers/handler"
 	"github.com/mpyw/goroutinectx/internal/checkers/spawnerlabel"
+	"github.com/mpyw/goroutinectx/internal/config"
 	"github.com/mpyw/goroutinectx/internal/deriver"
 	"github.com/mpyw/goroutinectx/internal/directive/carrier"
 	"github.com/mpyw/goroutinectx/internal/directive/ignore"
 	"github.com/mpyw/goroutinectx/internal/directive/spawner"
+	"github.com/mpyw/goroutinectx/internal/facts"
+	"github.com/mpyw/goroutinectx/internal/funcspec"
+	"github.com/mpyw/goroutinectx/internal/gitdiff"
 	"github.com/mpyw/goroutinectx/internal/registry"
 	"github.com/mpyw/goroutinectx/internal/ssa"
 )
 
 // Flags for the analyzer.
 var (
-	goroutineDeriver string
-	externalSpawner  string
-	contextCarriers  string
+	// cfg holds the flags shared with the graph analyzer.
+	cfg config.Config
+
+	goroutineDeriverUsed bool
+	requireIgnoreReason  bool
+	baselineFile         string
+	writeBaseline        bool
+	newFromRev           string
+	handlerFuncs         string
+	ctxfieldAllow        string
 
 	// Checker enable/disable flags (all enabled by default).
 	enableGoroutine    bool
@@ -37,15 +52,30 @@ var (
 	enableSpawner      bool
 	enableSpawnerlabel bool
 	enableGotask       bool
+	enableAdmission    bool
+	enableHandler      bool
+	enableCtxfield     bool
+	enableStdlib       bool
+	enableLoopCancel   bool
 )
 
 func init() {
-	Analyzer.Flags.StringVar(&goroutineDeriver, "goroutine-deriver", "",
-		"require goroutines to call this function to derive context (e.g., pkg.Func or pkg.Type.Method)")
-	Analyzer.Flags.StringVar(&externalSpawner, "external-spawner", "",
-		"comma-separated list of external spawner functions (e.g., pkg.Func or pkg.Type.Method)")
-	Analyzer.Flags.StringVar(&contextCarriers, "context-carriers", "",
-		"comma-separated list of types to treat as context carriers (e.g., github.com/labstack/echo/v4.Context)")
+	cfg.RegisterFlags(&Analyzer.Flags)
+
+	Analyzer.Flags.BoolVar(&goroutineDeriverUsed, "goroutine-deriver-used", false,
+		"report goroutines that call the deriver but discard the derived context instead of passing it to subsequent calls")
+	Analyzer.Flags.BoolVar(&requireIgnoreReason, "require-ignore-reason", false,
+		"report ignore directives without a reason (e.g., //goroutinectx:ignore - reason)")
+	Analyzer.Flags.StringVar(&baselineFile, "baseline", "",
+		"JSON file of known diagnostics to suppress; entries that no longer occur are reported")
+	Analyzer.Flags.BoolVar(&writeBaseline, "write-baseline", false,
+		"record current diagnostics into the -baseline file instead of reporting them")
+	Analyzer.Flags.StringVar(&newFromRev, "new-from-rev", "",
+		"only report diagnostics on lines changed since this git revision (e.g., origin/main); uses the local git diff")
+	Analyzer.Flags.StringVar(&handlerFuncs, "handler-funcs", "",
+		"comma-separated list of request handler functions, or registration functions whose func literal arguments are handlers, for the handler checker (e.g., pkg.Func or pkg.Type.Method)")
+	Analyzer.Flags.StringVar(&ctxfieldAllow, "ctxfield-allow", "",
+		"comma-separated list of struct types allowed to store a context.Context for the ctxfield checker (e.g., github.com/my-example-app/jobs.Request)")
 
 	// Checker flags (default: all enabled)
 	Analyzer.Flags.BoolVar(&enableGoroutine, "goroutine", true, "enable goroutine checker")
@@ -55,63 +85,113 @@ func init() {
 	Analyzer.Flags.BoolVar(&enableSpawner, "spawner", true, "enable spawner checker")
 	Analyzer.Flags.BoolVar(&enableSpawnerlabel, "spawnerlabel", false, "enable spawnerlabel checker")
 	Analyzer.Flags.BoolVar(&enableGotask, "gotask", true, "enable gotask checker (requires -goroutine-deriver)")
+	Analyzer.Flags.BoolVar(&enableStdlib, "stdlib", false, "enable stdlib checker (time.AfterFunc, context.AfterFunc, runtime.SetFinalizer, ... callbacks)")
+	Analyzer.Flags.BoolVar(&enableLoopCancel, "loopcancel", false, "enable loopcancel checker (goroutines in loops using a per-iteration context whose cancel is deferred)")
+	Analyzer.Flags.BoolVar(&enableAdmission, "admission", false, "enable admission checker (semaphores and limited errgroups must respect context)")
+	Analyzer.Flags.BoolVar(&enableHandler, "handler", false, "enable handler checker (goroutines capturing a request context must not outlive the handler)")
+	Analyzer.Flags.BoolVar(&enableCtxfield, "ctxfield", false, "enable ctxfield checker (structs storing a context.Context must not be captured by goroutines)")
 }
 
 // Analyzer is the main analyzer for goroutinectx.
 var Analyzer = &analysis.Analyzer{
 	Name:     "goroutinectx",
 	Doc:      "checks that context.Context is properly propagated to downstream calls",
-	Requires: []*analysis.Analyzer{inspect.Analyzer, ssa.BuildSSAAnalyzer},
+	Requires: []*analysis.Analyzer{inspect.Analyzer, ssa.BuildSSAAnalyzer, facts.Analyzer},
 	Run:      run,
 	Flags:    flag.FlagSet{},
 }
 
 var ErrNoInspector = errors.New("inspector analyzer result not found")
 
+var ErrNoFacts = errors.New("facts analyzer result not found")
+
+var ErrWriteBaselineWithNewFromRev = errors.New("-write-baseline cannot be combined with -new-from-rev")
+
 func run(pass *analysis.Pass) (any, error) {
 	insp, ok := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
 	if !ok {
 		return nil, ErrNoInspector
 	}
 
+	factsResult, ok := pass.ResultOf[facts.Analyzer].(*facts.Result)
+	if !ok {
+		return nil, ErrNoFacts
+	}
+
+	if writeBaseline && newFromRev != "" {
+		return nil, ErrWriteBaselineWithNewFromRev
+	}
+
+	// Filter diagnostics through the baseline file
+	var session *baseline.Session
+	if baselineFile != "" {
+		s, err := baseline.NewSession(pass, baselineFile, writeBaseline)
+		if err != nil {
+			return nil, err
+		}
+		session = s
+		pass = s.Pass()
+	}
+
+	// Load lines changed since -new-from-rev
+	changes, err := loadChanges(pass)
+	if err != nil {
+		return nil, err
+	}
+
 	// Build set of files to skip
 	skipFiles := buildSkipFiles(pass)
 
-	// Parse configuration
-	carriers := carrier.Parse(contextCarriers)
+	// Parse configuration, adding carriers declared by //goroutinectx:carrier directives
+	carriers := cfg.Carriers(pass, factsResult)
 
 	// Build ignore maps for each file (excluding skipped files)
 	ignoreMaps := buildIgnoreMaps(pass, skipFiles)
 
 	// Build spawner map from //goroutinectx:spawner directives and -external-spawner flag
-	spawners := spawner.Build(pass, externalSpawner)
+	spawners := cfg.Spawners(pass)
+
+	// Build deriver rules from -goroutine-deriver and -goroutine-deriver-rules flags
+	// and //goroutinectx:deriver directives
+	derivers, err := cfg.Derivers(factsResult)
+	if err != nil {
+		return nil, err
+	}
 
 	// Build enabled checkers map
-	enabled := buildEnabledCheckers(spawners)
+	enabled := buildEnabledCheckers(derivers, spawners)
 
 	// Build SSA program
 	ssaProg := ssa.Build(pass)
 
-	// Build derivers matcher
-	var derivers *deriver.Matcher
-	if goroutineDeriver != "" {
-		derivers = deriver.NewMatcher(goroutineDeriver)
-	}
+	// Parse concurrent iterators from -concurrent-iterator flag
+	iterators := cfg.Iterators()
+
+	// Parse detach functions from -detach-funcs flag
+	detachers := cfg.Detachers()
 
 	// Build checkers
-	goStmtCheckers, callCheckers := buildCheckers(derivers, spawners)
+	goStmtCheckers, callCheckers, rangeCheckers := buildCheckers(derivers, spawners, iterators)
 
 	// Create and run runner
 	runner := internal.NewRunner(
 		goStmtCheckers,
 		callCheckers,
+		rangeCheckers,
 		ssaProg,
 		carriers,
+		detachers,
+		cfg.ContextFields,
+		spawners,
 		ignoreMaps,
 		skipFiles,
+		changes,
 	)
 	runner.Run(pass, insp)
 
+	// Reports below are not filtered by the runner
+	changedPass := filterChanges(pass, changes)
+
 	// Run spawnerlabel checker if enabled
 	if enableSpawnerlabel {
 		reg := registry.New()
@@ -123,15 +203,94 @@ func run(pass *analysis.Pass) (any, error) {
 		internal.RegisterGotaskAPIs(reg)
 
 		spawnerlabelChecker := spawnerlabel.New(spawners, reg, ssaProg)
-		spawnerlabelChecker.Check(pass, ignoreMaps, skipFiles)
+		spawnerlabelChecker.Check(changedPass, ignoreMaps, skipFiles)
+	}
+
+	// Run handler checker if enabled
+	if enableHandler {
+		reg := registry.New()
+
+		// Register APIs whose callbacks may capture the request context
+		internal.RegisterErrgroupAPIs(reg)
+		internal.RegisterWaitgroupAPIs(reg)
+		internal.RegisterConcAPIs(reg)
+
+		handlerChecker := handler.New(funcspec.ParseList(handlerFuncs), reg, carriers, detachers)
+		handlerChecker.Check(changedPass, ignoreMaps, skipFiles)
+	}
+
+	// Run ctxfield checker if enabled
+	if enableCtxfield {
+		reg := registry.New()
+
+		// Register APIs whose callbacks run on other goroutines
+		internal.RegisterErrgroupAPIs(reg)
+		internal.RegisterWaitgroupAPIs(reg)
+		internal.RegisterConcAPIs(reg)
+
+		ctxfieldChecker := ctxfield.New(reg, carrier.Parse(ctxfieldAllow), carriers)
+		ctxfieldChecker.Check(changedPass, ignoreMaps, skipFiles)
 	}
 
+	// Report carrier directives naming an invalid accessor
+	reportInvalidCarrierAccessors(changedPass, skipFiles)
+
+	// Report unknown checker names in ignore directives
+	reportUnknownIgnoreCheckers(changedPass, ignoreMaps)
+
 	// Report unused ignore directives
-	reportUnusedIgnores(pass, ignoreMaps, enabled)
+	reportUnusedIgnores(changedPass, ignoreMaps, enabled)
+
+	// Report ignore regions without a matching begin or end
+	reportUnmatchedIgnoreRegions(changedPass, ignoreMaps)
+
+	// Report ignore directives without a reason
+	if requireIgnoreReason {
+		reportMissingIgnoreReasons(changedPass, ignoreMaps)
+	}
+
+	// Write the baseline, or report baseline entries that no longer occur.
+	// Under -new-from-rev, findings on unchanged lines never reach the baseline,
+	// so its entries cannot be told stale.
+	if session != nil && changes == nil {
+		if err := session.Finish(); err != nil {
+			return nil, err
+		}
+	}
 
 	return nil, nil
 }
 
+// loadChanges returns the lines changed since -new-from-rev in the git
+// repository containing the package, or nil if the flag is not set.
+func loadChanges(pass *analysis.Pass) (*gitdiff.Changes, error) {
+	if newFromRev == "" || len(pass.Files) == 0 {
+		return nil, nil
+	}
+
+	filename := pass.Fset.Position(pass.Files[0].Pos()).Filename
+
+	return gitdiff.Load(filepath.Dir(filename), newFromRev)
+}
+
+// filterChanges returns a copy of pass that drops diagnostics outside the changed lines.
+// Returns pass itself if changes is nil.
+func filterChanges(pass *analysis.Pass, changes *gitdiff.Changes) *analysis.Pass {
+	if changes == nil {
+		return pass
+	}
+
+	filtered := *pass
+	filtered.Report = func(d analysis.Diagnostic) {
+		position := pass.Fset.Position(d.Pos)
+		if changes.Contains(position.Filename, position.Line) {
+			pass.Report(d)
+		}
+	}
+
+	return &filtered
+}
+
 // buildSkipFiles creates a set of filenames to skip.
 func buildSkipFiles(pass *analysis.Pass) map[string]bool {
 	skipFiles := make(map[string]bool)
@@ -163,54 +322,87 @@ func buildIgnoreMaps(pass *analysis.Pass, skipFiles map[string]bool) map[string]
 }
 
 // buildCheckers creates the checker instances.
-func buildCheckers(derivers *deriver.Matcher, spawners *spawner.Map) ([]internal.GoStmtChecker, []internal.CallChecker) {
+func buildCheckers(
+	derivers *deriver.Rules,
+	spawners *spawner.Map,
+	iterators []funcspec.Spec,
+) ([]internal.GoStmtChecker, []internal.CallChecker, []internal.RangeStmtChecker) {
 	var goStmtCheckers []internal.GoStmtChecker
 	var callCheckers []internal.CallChecker
+	var rangeCheckers []internal.RangeStmtChecker
 
 	// Goroutine checkers
 	if enableGoroutine {
 		goStmtCheckers = append(goStmtCheckers, &checkers.Goroutine{})
 	}
 
-	if derivers != nil {
-		goStmtCheckers = append(goStmtCheckers, checkers.NewGoroutineDerive(derivers))
+	if goroutineDerivers := derivers.ForChecker(ignore.Goroutine); goroutineDerivers != nil && !goroutineDerivers.IsEmpty() {
+		goStmtCheckers = append(goStmtCheckers, checkers.NewGoroutineDerive(goroutineDerivers, goroutineDeriverUsed))
 	}
 
 	// Call checkers
 	if enableErrgroup {
-		callCheckers = append(callCheckers, checkers.NewErrgroupChecker(derivers))
+		callCheckers = append(callCheckers, checkers.NewErrgroupChecker(derivers, goroutineDeriverUsed))
 	}
 
 	if enableWaitgroup {
-		callCheckers = append(callCheckers, checkers.NewWaitgroupChecker(derivers))
+		callCheckers = append(callCheckers, checkers.NewWaitgroupChecker(derivers, goroutineDeriverUsed))
 	}
 
 	if enableConc {
-		callCheckers = append(callCheckers, checkers.NewConcChecker(derivers))
+		callCheckers = append(callCheckers, checkers.NewConcChecker(derivers, goroutineDeriverUsed))
 	}
 
 	if enableSpawner && spawners.Len() > 0 {
-		callCheckers = append(callCheckers, checkers.NewSpawnerChecker(spawners, derivers))
+		callCheckers = append(callCheckers, checkers.NewSpawnerChecker(spawners, derivers.ForChecker(ignore.Spawner), goroutineDeriverUsed))
 	}
 
-	if enableGotask && derivers != nil {
-		if gotaskChecker := checkers.NewGotaskChecker(derivers); gotaskChecker != nil {
+	if enableGotask {
+		if gotaskChecker := checkers.NewGotaskChecker(derivers.ForChecker(ignore.Gotask), goroutineDeriverUsed); gotaskChecker != nil {
 			callCheckers = append(callCheckers, gotaskChecker)
 		}
 	}
 
-	return goStmtCheckers, callCheckers
+	if enableStdlib {
+		callCheckers = append(callCheckers, &checkers.Stdlib{})
+	}
+
+	if enableLoopCancel {
+		reg := registry.New()
+
+		// Register APIs whose callbacks run on other goroutines
+		internal.RegisterErrgroupAPIs(reg)
+		internal.RegisterWaitgroupAPIs(reg)
+		internal.RegisterConcAPIs(reg)
+
+		loopCancel := checkers.NewLoopCancel(reg)
+		goStmtCheckers = append(goStmtCheckers, loopCancel)
+		callCheckers = append(callCheckers, loopCancel)
+	}
+
+	if enableAdmission {
+		admission := &checkers.Admission{}
+		goStmtCheckers = append(goStmtCheckers, admission)
+		callCheckers = append(callCheckers, admission)
+	}
+
+	// Range checkers
+	if len(iterators) > 0 {
+		rangeCheckers = append(rangeCheckers, checkers.NewIteratorChecker(iterators, derivers.ForChecker(ignore.Iterator), goroutineDeriverUsed))
+	}
+
+	return goStmtCheckers, callCheckers, rangeCheckers
 }
 
 // buildEnabledCheckers creates a map of which checkers are enabled.
-func buildEnabledCheckers(spawners *spawner.Map) ignore.EnabledCheckers {
+func buildEnabledCheckers(derivers *deriver.Rules, spawners *spawner.Map) ignore.EnabledCheckers {
 	enabled := make(ignore.EnabledCheckers)
 
 	if enableGoroutine {
 		enabled[ignore.Goroutine] = true
 	}
 
-	if goroutineDeriver != "" {
+	if goroutineDerivers := derivers.ForChecker(ignore.Goroutine); goroutineDerivers != nil && !goroutineDerivers.IsEmpty() {
 		enabled[ignore.GoroutineDerive] = true
 	}
 
@@ -218,10 +410,14 @@ func buildEnabledCheckers(spawners *spawner.Map) ignore.EnabledCheckers {
 		enabled[ignore.Waitgroup] = true
 	}
 
-	if enableErrgroup || enableConc {
+	if enableErrgroup {
 		enabled[ignore.Errgroup] = true
 	}
 
+	if enableConc {
+		enabled[ignore.Conc] = true
+	}
+
 	if enableSpawner && spawners.Len() > 0 {
 		enabled[ignore.Spawner] = true
 	}
@@ -230,10 +426,34 @@ func buildEnabledCheckers(spawners *spawner.Map) ignore.EnabledCheckers {
 		enabled[ignore.Spawnerlabel] = true
 	}
 
-	if goroutineDeriver != "" && enableGotask {
+	if gotaskDerivers := derivers.ForChecker(ignore.Gotask); gotaskDerivers != nil && !gotaskDerivers.IsEmpty() && enableGotask {
 		enabled[ignore.Gotask] = true
 	}
 
+	if enableStdlib {
+		enabled[ignore.Stdlib] = true
+	}
+
+	if enableLoopCancel {
+		enabled[ignore.LoopCancel] = true
+	}
+
+	if enableAdmission {
+		enabled[ignore.Admission] = true
+	}
+
+	if cfg.ConcurrentIterators != "" {
+		enabled[ignore.Iterator] = true
+	}
+
+	if enableHandler {
+		enabled[ignore.Handler] = true
+	}
+
+	if enableCtxfield {
+		enabled[ignore.Ctxfield] = true
+	}
+
 	return enabled
 }
 
@@ -253,3 +473,50 @@ func reportUnusedIgnores(pass *analysis.Pass, ignoreMaps map[string]ignore.Map,
 		}
 	}
 }
+
+// reportUnmatchedIgnoreRegions reports unterminated ignore-begin directives
+// and ignore-end directives closing no region.
+func reportUnmatchedIgnoreRegions(pass *analysis.Pass, ignoreMaps map[string]ignore.Map) {
+	for _, ignoreMap := range ignoreMaps {
+		for _, unmatched := range ignoreMap.GetUnmatchedRegions() {
+			if unmatched.Directive == "goroutinectx:ignore-end" {
+				pass.Reportf(unmatched.Pos, "goroutinectx:ignore-end directive without a matching goroutinectx:ignore-begin")
+			} else {
+				pass.Reportf(unmatched.Pos, "goroutinectx:ignore-begin directive without a matching goroutinectx:ignore-end; it covers the rest of the file")
+			}
+		}
+	}
+}
+
+// reportUnknownIgnoreCheckers reports checker names in ignore directives that are not registered.
+func reportUnknownIgnoreCheckers(pass *analysis.Pass, ignoreMaps map[string]ignore.Map) {
+	for _, ignoreMap := range ignoreMaps {
+		for _, unknown := range ignoreMap.GetUnknownCheckers() {
+			if unknown.Suggestion != "" {
+				pass.Reportf(unknown.Pos, "unknown checker %q in goroutinectx:ignore directive (did you mean %q?)", unknown.Name, unknown.Suggestion)
+			} else {
+				pass.Reportf(unknown.Pos, "unknown checker %q in goroutinectx:ignore directive", unknown.Name)
+			}
+		}
+	}
+}
+
+// reportInvalidCarrierAccessors reports //goroutinectx:carrier directives whose
+// accessor is not a method of the type returning a context.Context.
+func reportInvalidCarrierAccessors(pass *analysis.Pass, skipFiles map[string]bool) {
+	for _, d := range carrier.Build(pass) {
+		if skipFiles[pass.Fset.Position(d.Pos).Filename] || d.ValidAccessor() {
+			continue
+		}
+		pass.Reportf(d.Pos, "goroutinectx:carrier accessor %q is not a method of %s returning context.Context", d.Accessor, d.Type.Name())
+	}
+}
+
+// reportMissingIgnoreReasons reports ignore directives without a " - reason" text.
+func reportMissingIgnoreReasons(pass *analysis.Pass, ignoreMaps map[string]ignore.Map) {
+	for _, ignoreMap := range ignoreMaps {
+		for _, missing := range ignoreMap.GetMissingReasons() {
+			pass.Reportf(missing.Pos, "%s directive should have a reason (e.g., //%s - reason)", missing.Directive, missing.Directive)
+		}
+	}
+}
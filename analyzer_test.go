@@ -1,6 +1,10 @@
 package goroutinectx_test
 
 import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
 	"testing"
 
 	"golang.org/x/tools/go/analysis/analysistest"
@@ -33,6 +37,27 @@ func TestErrgroupDerive(t *testing.T) {
 	analysistest.Run(t, testdata, goroutinectx.Analyzer, "errgroupderive")
 }
 
+func TestGoroutineDeriverRules(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	deriveFunc := "github.com/my-example-app/telemetry/apm.NewGoroutineContext"
+	if err := goroutinectx.Analyzer.Flags.Set("goroutine-deriver", deriveFunc); err != nil {
+		t.Fatal(err)
+	}
+
+	rules := "golang.org/x/sync/errgroup.Group.Go=github.com/my-example-app/telemetry/apm.NewSegmentContext;spawner="
+	if err := goroutinectx.Analyzer.Flags.Set("goroutine-deriver-rules", rules); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_ = goroutinectx.Analyzer.Flags.Set("goroutine-deriver", "")
+		_ = goroutinectx.Analyzer.Flags.Set("goroutine-deriver-rules", "")
+	}()
+
+	analysistest.Run(t, testdata, goroutinectx.Analyzer, "deriverrules")
+}
+
 func TestConc(t *testing.T) {
 	testdata := analysistest.TestData()
 	analysistest.Run(t, testdata, goroutinectx.Analyzer, "conc")
@@ -86,6 +111,99 @@ func TestGoroutineDeriveMixed(t *testing.T) {
 	analysistest.Run(t, testdata, goroutinectx.Analyzer, "goroutinederivemixed")
 }
 
+func TestDeriverOrder(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	deriveFunc := "github.com/my-example-app/telemetry/apm.NewGoroutineContext"
+	if err := goroutinectx.Analyzer.Flags.Set("goroutine-deriver", deriveFunc); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_ = goroutinectx.Analyzer.Flags.Set("goroutine-deriver", "")
+	}()
+
+	analysistest.Run(t, testdata, goroutinectx.Analyzer, "deriverorder")
+}
+
+func TestDeriverUsed(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	deriveFunc := "github.com/my-example-app/telemetry/apm.NewGoroutineContext"
+	if err := goroutinectx.Analyzer.Flags.Set("goroutine-deriver", deriveFunc); err != nil {
+		t.Fatal(err)
+	}
+	if err := goroutinectx.Analyzer.Flags.Set("goroutine-deriver-used", "true"); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_ = goroutinectx.Analyzer.Flags.Set("goroutine-deriver", "")
+		_ = goroutinectx.Analyzer.Flags.Set("goroutine-deriver-used", "false")
+	}()
+
+	analysistest.Run(t, testdata, goroutinectx.Analyzer, "deriverused")
+}
+
+func TestDeriverWrapper(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	deriveFunc := "github.com/my-example-app/telemetry/apm.NewGoroutineContext"
+	if err := goroutinectx.Analyzer.Flags.Set("goroutine-deriver", deriveFunc); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_ = goroutinectx.Analyzer.Flags.Set("goroutine-deriver", "")
+	}()
+
+	analysistest.Run(t, testdata, goroutinectx.Analyzer, "deriverwrapper")
+}
+
+func TestDeriverDirective(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, goroutinectx.Analyzer, "deriverdirective", "deriverdirectivetransitive")
+}
+
+func TestNestedSpawn(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, goroutinectx.Analyzer, "nestedspawn")
+}
+
+func TestNestedDerive(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	deriveFunc := "github.com/my-example-app/telemetry/apm.NewGoroutineContext"
+	if err := goroutinectx.Analyzer.Flags.Set("goroutine-deriver", deriveFunc); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_ = goroutinectx.Analyzer.Flags.Set("goroutine-deriver", "")
+	}()
+
+	analysistest.Run(t, testdata, goroutinectx.Analyzer, "nestedderive")
+}
+
+func TestNestedDeriveOnce(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	deriveFunc := "github.com/my-example-app/telemetry/apm.NewGoroutineContext"
+	if err := goroutinectx.Analyzer.Flags.Set("goroutine-deriver", deriveFunc); err != nil {
+		t.Fatal(err)
+	}
+	if err := goroutinectx.Analyzer.Flags.Set("goroutine-deriver-once", "true"); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_ = goroutinectx.Analyzer.Flags.Set("goroutine-deriver", "")
+		_ = goroutinectx.Analyzer.Flags.Set("goroutine-deriver-once", "false")
+	}()
+
+	analysistest.Run(t, testdata, goroutinectx.Analyzer, "nestedderiveonce")
+}
+
 func TestContextCarriers(t *testing.T) {
 	testdata := analysistest.TestData()
 
@@ -101,6 +219,40 @@ func TestContextCarriers(t *testing.T) {
 	analysistest.Run(t, testdata, goroutinectx.Analyzer, "carrier")
 }
 
+func TestCarrierDirective(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, goroutinectx.Analyzer, "carrierdirective")
+}
+
+func TestCarrierInterface(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	carriers := "github.com/labstack/echo/v4.Context"
+	if err := goroutinectx.Analyzer.Flags.Set("context-carriers", carriers); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_ = goroutinectx.Analyzer.Flags.Set("context-carriers", "")
+	}()
+
+	analysistest.Run(t, testdata, goroutinectx.Analyzer, "carrierinterface")
+}
+
+func TestCarrierAuto(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	if err := goroutinectx.Analyzer.Flags.Set("context-carriers-auto", "true"); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_ = goroutinectx.Analyzer.Flags.Set("context-carriers-auto", "false")
+	}()
+
+	analysistest.Run(t, testdata, goroutinectx.Analyzer, "carrierauto")
+}
+
 func TestCarrierDerive(t *testing.T) {
 	testdata := analysistest.TestData()
 
@@ -193,3 +345,349 @@ func TestFileFilter(t *testing.T) {
 	// Tests that generated files are skipped
 	analysistest.Run(t, testdata, goroutinectx.Analyzer, "filefilter")
 }
+
+func TestAdmission(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	if err := goroutinectx.Analyzer.Flags.Set("admission", "true"); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_ = goroutinectx.Analyzer.Flags.Set("admission", "false")
+	}()
+
+	analysistest.Run(t, testdata, goroutinectx.Analyzer, "admission")
+}
+
+func TestHandler(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	if err := goroutinectx.Analyzer.Flags.Set("handler", "true"); err != nil {
+		t.Fatal(err)
+	}
+	if err := goroutinectx.Analyzer.Flags.Set("handler-funcs", "handler.route"); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_ = goroutinectx.Analyzer.Flags.Set("handler", "false")
+		_ = goroutinectx.Analyzer.Flags.Set("handler-funcs", "")
+	}()
+
+	analysistest.Run(t, testdata, goroutinectx.Analyzer, "handler")
+}
+
+func TestDetachFuncs(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	deriveFunc := "github.com/my-example-app/telemetry/apm.NewGoroutineContext"
+	if err := goroutinectx.Analyzer.Flags.Set("goroutine-deriver", deriveFunc); err != nil {
+		t.Fatal(err)
+	}
+	detachFuncs := "context.WithoutCancel,github.com/my-example-app/ctxutil.Detach"
+	if err := goroutinectx.Analyzer.Flags.Set("detach-funcs", detachFuncs); err != nil {
+		t.Fatal(err)
+	}
+	if err := goroutinectx.Analyzer.Flags.Set("handler", "true"); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_ = goroutinectx.Analyzer.Flags.Set("goroutine-deriver", "")
+		_ = goroutinectx.Analyzer.Flags.Set("detach-funcs", "")
+		_ = goroutinectx.Analyzer.Flags.Set("handler", "false")
+	}()
+
+	analysistest.Run(t, testdata, goroutinectx.Analyzer, "detach")
+}
+
+func TestContextFields(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	if err := goroutinectx.Analyzer.Flags.Set("context-fields", "true"); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_ = goroutinectx.Analyzer.Flags.Set("context-fields", "false")
+	}()
+
+	analysistest.Run(t, testdata, goroutinectx.Analyzer, "contextfields")
+}
+
+func TestCtxfield(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	if err := goroutinectx.Analyzer.Flags.Set("ctxfield", "true"); err != nil {
+		t.Fatal(err)
+	}
+	if err := goroutinectx.Analyzer.Flags.Set("ctxfield-allow", "ctxfield.allowedJob"); err != nil {
+		t.Fatal(err)
+	}
+	if err := goroutinectx.Analyzer.Flags.Set("context-carriers", "ctxfield.requestScope"); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_ = goroutinectx.Analyzer.Flags.Set("ctxfield", "false")
+		_ = goroutinectx.Analyzer.Flags.Set("ctxfield-allow", "")
+		_ = goroutinectx.Analyzer.Flags.Set("context-carriers", "")
+	}()
+
+	analysistest.Run(t, testdata, goroutinectx.Analyzer, "ctxfield")
+}
+
+func TestStdlib(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	if err := goroutinectx.Analyzer.Flags.Set("stdlib", "true"); err != nil {
+		t.Fatal(err)
+	}
+	if err := goroutinectx.Analyzer.Flags.Set("detach-funcs", "github.com/my-example-app/ctxutil.Detach"); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_ = goroutinectx.Analyzer.Flags.Set("stdlib", "false")
+		_ = goroutinectx.Analyzer.Flags.Set("detach-funcs", "")
+	}()
+
+	analysistest.Run(t, testdata, goroutinectx.Analyzer, "stdlib")
+}
+
+func TestLoopCancel(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	if err := goroutinectx.Analyzer.Flags.Set("loopcancel", "true"); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_ = goroutinectx.Analyzer.Flags.Set("loopcancel", "false")
+	}()
+
+	analysistest.Run(t, testdata, goroutinectx.Analyzer, "loopcancel")
+}
+
+func TestConcurrentIterator(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	iterators := "github.com/example/parallel.Each," +
+		"github.com/example/parallel.Group.Range"
+	if err := goroutinectx.Analyzer.Flags.Set("concurrent-iterator", iterators); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_ = goroutinectx.Analyzer.Flags.Set("concurrent-iterator", "")
+	}()
+
+	analysistest.Run(t, testdata, goroutinectx.Analyzer, "iterator")
+}
+
+func TestConcurrentIteratorDerive(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	if err := goroutinectx.Analyzer.Flags.Set("concurrent-iterator", "github.com/example/parallel.Each"); err != nil {
+		t.Fatal(err)
+	}
+	deriveFunc := "github.com/my-example-app/telemetry/apm.NewGoroutineContext"
+	if err := goroutinectx.Analyzer.Flags.Set("goroutine-deriver", deriveFunc); err != nil {
+		t.Fatal(err)
+	}
+	if err := goroutinectx.Analyzer.Flags.Set("detach-funcs", "github.com/my-example-app/ctxutil.Detach"); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_ = goroutinectx.Analyzer.Flags.Set("concurrent-iterator", "")
+		_ = goroutinectx.Analyzer.Flags.Set("goroutine-deriver", "")
+		_ = goroutinectx.Analyzer.Flags.Set("detach-funcs", "")
+	}()
+
+	analysistest.Run(t, testdata, goroutinectx.Analyzer, "iteratorderive")
+}
+
+func TestGeneric(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	if err := goroutinectx.Analyzer.Flags.Set("goroutine-deriver", "generic.Derive"); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_ = goroutinectx.Analyzer.Flags.Set("goroutine-deriver", "")
+	}()
+
+	analysistest.Run(t, testdata, goroutinectx.Analyzer, "generic")
+}
+
+func TestIgnoreDirective(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, goroutinectx.Analyzer, "ignoredirective", "ignorefile")
+}
+
+func TestIgnoreCheckerNames(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, goroutinectx.Analyzer, "ignorenames")
+}
+
+func TestRequireIgnoreReason(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	if err := goroutinectx.Analyzer.Flags.Set("require-ignore-reason", "true"); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_ = goroutinectx.Analyzer.Flags.Set("require-ignore-reason", "false")
+	}()
+
+	analysistest.Run(t, testdata, goroutinectx.Analyzer, "ignorereason")
+}
+
+func TestBaseline(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	baselineFile := filepath.Join(testdata, "src", "baseline", "goroutinectx-baseline.json")
+	if err := goroutinectx.Analyzer.Flags.Set("baseline", baselineFile); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_ = goroutinectx.Analyzer.Flags.Set("baseline", "")
+	}()
+
+	analysistest.Run(t, testdata, goroutinectx.Analyzer, "baseline")
+}
+
+func TestWriteBaseline(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	baselineFile := filepath.Join(t.TempDir(), "goroutinectx-baseline.json")
+	if err := goroutinectx.Analyzer.Flags.Set("baseline", baselineFile); err != nil {
+		t.Fatal(err)
+	}
+	if err := goroutinectx.Analyzer.Flags.Set("write-baseline", "true"); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_ = goroutinectx.Analyzer.Flags.Set("baseline", "")
+		_ = goroutinectx.Analyzer.Flags.Set("write-baseline", "false")
+	}()
+
+	// Findings are recorded instead of reported, so no diagnostics are expected
+	analysistest.Run(t, testdata, goroutinectx.Analyzer, "baselinewrite")
+
+	data, err := os.ReadFile(baselineFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got struct {
+		Entries []struct {
+			Package  string `json:"package"`
+			File     string `json:"file"`
+			Function string `json:"function"`
+			Checker  string `json:"checker"`
+			Count    int    `json:"count"`
+		} `json:"entries"`
+	}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	type entry struct {
+		file, function, checker string
+		count                   int
+	}
+	// The package and its test variant both write the entries of baselinewrite.go;
+	// the entry of the test file only the test variant sees is kept
+	want := []entry{
+		{"baselinewrite.go", "(*server).handle", "errgroup", 1},
+		{"baselinewrite.go", "legacyWorker", "goroutine", 2},
+		{"baselinewrite_test.go", "helper", "goroutine", 1},
+	}
+
+	if len(got.Entries) != len(want) {
+		t.Fatalf("got %d entries, want %d:\n%s", len(got.Entries), len(want), data)
+	}
+	for i, e := range got.Entries {
+		if e.Package != "baselinewrite" || (entry{e.File, e.Function, e.Checker, e.Count}) != want[i] {
+			t.Errorf("entry %d = %+v, want %+v in package baselinewrite", i, e, want[i])
+		}
+	}
+}
+
+func TestNewFromRev(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	// Build a GOPATH-style testdata directory inside a fresh git repository
+	dir := t.TempDir()
+	pkgDir := filepath.Join(dir, "src", "newfromrev")
+	if err := os.MkdirAll(pkgDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	const base = `package newfromrev
+
+import "context"
+
+func oldGoroutine(ctx context.Context) {
+	go func() {
+	}()
+}
+
+func oldUnusedIgnore(ctx context.Context) {
+	//goroutinectx:ignore
+	_ = ctx
+}
+`
+
+	const added = `
+func newGoroutine(ctx context.Context) {
+	go func() { // want "goroutine does not propagate context .ctx."
+	}()
+}
+
+func newUnusedIgnore(ctx context.Context) {
+	//goroutinectx:ignore // want "unused goroutinectx:ignore directive"
+	_ = ctx
+}
+`
+
+	filename := filepath.Join(pkgDir, "newfromrev.go")
+	if err := os.WriteFile(filename, []byte(base), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com", "-C", dir}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	git("init", "-q")
+	git("add", "-A")
+	git("commit", "-q", "-m", "base")
+
+	if err := os.WriteFile(filename, []byte(base+added), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := goroutinectx.Analyzer.Flags.Set("new-from-rev", "HEAD"); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_ = goroutinectx.Analyzer.Flags.Set("new-from-rev", "")
+	}()
+
+	// Only findings on lines added after HEAD are reported
+	analysistest.Run(t, dir, goroutinectx.Analyzer, "newfromrev")
+}
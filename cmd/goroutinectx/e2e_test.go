@@ -1,6 +1,7 @@
 package main_test
 
 import (
+	"encoding/json"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -291,3 +292,251 @@ func TestE2E_DisableSpawnerChecker(t *testing.T) {
 		t.Errorf("expected zero exit code when spawner checker disabled, got error: %v\noutput:\n%s", err, out)
 	}
 }
+
+func TestE2E_GraphJSON(t *testing.T) {
+	testdata := filepath.Join(getE2ETestdata(), "graph")
+
+	cmd := exec.Command(binaryPath, "graph",
+		"-format=json",
+		"-goroutine-deriver=example.com/graph/apm.NewGoroutineContext",
+		"./...",
+	)
+	cmd.Dir = testdata
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("unexpected error: %v\noutput:\n%s", err, out)
+	}
+
+	var sites []struct {
+		Func            string `json:"func"`
+		API             string `json:"api"`
+		Callee          string `json:"callee"`
+		CapturesContext bool   `json:"captures_context"`
+		CallsDeriver    *bool  `json:"calls_deriver"`
+	}
+	if err := json.Unmarshal(out, &sites); err != nil {
+		t.Fatalf("invalid JSON: %v\noutput:\n%s", err, out)
+	}
+
+	type site struct {
+		fn, api, callee        string
+		captures, callsDeriver bool
+	}
+	want := []site{
+		{"example.com/graph.handle", "go", "example.com/graph.handle$1", true, true},
+		{"example.com/graph.handle", "go", "example.com/graph.handle$2", false, false},
+		{"example.com/graph.handle", "go", "example.com/graph.worker", true, false},
+		{"example.com/graph.handle", "go", "example.com/graph.handle$3", false, false},
+		{"example.com/graph.dispatch", "sync.WaitGroup.Go", "example.com/graph.dispatch$1", true, false},
+		{"example.com/graph.dispatch", "example.com/graph.runAsync", "example.com/graph.dispatch$2", false, false},
+		{"example.com/graph.runAsync", "go", "fn", false, false},
+	}
+
+	if len(sites) != len(want) {
+		t.Fatalf("got %d sites, want %d:\n%s", len(sites), len(want), out)
+	}
+	for i, s := range sites {
+		if s.CallsDeriver == nil {
+			t.Errorf("site %d: calls_deriver missing although a deriver is configured", i)
+			continue
+		}
+		if got := (site{s.Func, s.API, s.Callee, s.CapturesContext, *s.CallsDeriver}); got != want[i] {
+			t.Errorf("site %d = %+v, want %+v", i, got, want[i])
+		}
+	}
+}
+
+func TestE2E_GraphConcurrentIterator(t *testing.T) {
+	testdata := filepath.Join(getE2ETestdata(), "graph")
+
+	cmd := exec.Command(binaryPath, "graph",
+		"-format=json",
+		"-concurrent-iterator=example.com/graph/parallel.Each",
+		"./...",
+	)
+	cmd.Dir = testdata
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("unexpected error: %v\noutput:\n%s", err, out)
+	}
+
+	var sites []struct {
+		Func            string `json:"func"`
+		API             string `json:"api"`
+		Checker         string `json:"checker"`
+		Callee          string `json:"callee"`
+		CapturesContext bool   `json:"captures_context"`
+	}
+	if err := json.Unmarshal(out, &sites); err != nil {
+		t.Fatalf("invalid JSON: %v\noutput:\n%s", err, out)
+	}
+
+	type site struct {
+		fn, api, callee string
+		captures        bool
+	}
+	want := []site{
+		{"example.com/graph.iterate", "example.com/graph/parallel.Each", "example.com/graph.iterate$1", true},
+		{"example.com/graph.iterate", "example.com/graph/parallel.Each", "example.com/graph.iterate$2", false},
+	}
+
+	var got []site
+	for _, s := range sites {
+		if s.Checker == "iterator" {
+			got = append(got, site{s.Func, s.API, s.Callee, s.CapturesContext})
+		}
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d iterator sites, want %d:\n%s", len(got), len(want), out)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("iterator site %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestE2E_GraphDOT(t *testing.T) {
+	testdata := filepath.Join(getE2ETestdata(), "graph")
+
+	cmd := exec.Command(binaryPath, "graph", "./...")
+	cmd.Dir = testdata
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("unexpected error: %v\noutput:\n%s", err, out)
+	}
+
+	output := string(out)
+
+	if !strings.HasPrefix(output, "digraph goroutinectx {") {
+		t.Errorf("expected DOT digraph, got:\n%s", output)
+	}
+
+	// A spawn site that drops the context is highlighted
+	wantEdge := `"example.com/graph.handle" -> "example.com/graph.handle$2" [label="go\ndoes not capture ctx", color=red];`
+	if !strings.Contains(output, wantEdge) {
+		t.Errorf("expected edge %s, got:\n%s", wantEdge, output)
+	}
+}
+
+func TestE2E_StatsJSON(t *testing.T) {
+	testdata := filepath.Join(getE2ETestdata(), "graph")
+
+	cmd := exec.Command(binaryPath, "-stats=json", "./...")
+	cmd.Dir = testdata
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("unexpected error: %v\noutput:\n%s", err, out)
+	}
+
+	type counts struct {
+		Sites             int `json:"sites"`
+		WithContext       int `json:"with_context"`
+		PropagatesContext int `json:"propagates_context"`
+		Ignored           int `json:"ignored"`
+	}
+	var stats struct {
+		Packages int               `json:"packages"`
+		Total    counts            `json:"total"`
+		Checkers map[string]counts `json:"checkers"`
+		APIs     map[string]counts `json:"apis"`
+	}
+	if err := json.Unmarshal(out, &stats); err != nil {
+		t.Fatalf("invalid JSON: %v\noutput:\n%s", err, out)
+	}
+
+	if want := (counts{Sites: 7, WithContext: 6, PropagatesContext: 3, Ignored: 1}); stats.Total != want {
+		t.Errorf("total = %+v, want %+v", stats.Total, want)
+	}
+	if want := (counts{Sites: 5, WithContext: 4, PropagatesContext: 2, Ignored: 1}); stats.Checkers["goroutine"] != want {
+		t.Errorf("checkers[goroutine] = %+v, want %+v", stats.Checkers["goroutine"], want)
+	}
+	if want := (counts{Sites: 1, WithContext: 1, PropagatesContext: 1}); stats.APIs["sync.WaitGroup.Go"] != want {
+		t.Errorf("apis[sync.WaitGroup.Go] = %+v, want %+v", stats.APIs["sync.WaitGroup.Go"], want)
+	}
+}
+
+func TestE2E_StatsConcurrentIterator(t *testing.T) {
+	testdata := filepath.Join(getE2ETestdata(), "graph")
+
+	cmd := exec.Command(binaryPath, "-stats=json", "-concurrent-iterator=example.com/graph/parallel.Each", "./...")
+	cmd.Dir = testdata
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("unexpected error: %v\noutput:\n%s", err, out)
+	}
+
+	type counts struct {
+		Sites             int `json:"sites"`
+		WithContext       int `json:"with_context"`
+		PropagatesContext int `json:"propagates_context"`
+	}
+	var stats struct {
+		Checkers map[string]counts `json:"checkers"`
+		APIs     map[string]counts `json:"apis"`
+	}
+	if err := json.Unmarshal(out, &stats); err != nil {
+		t.Fatalf("invalid JSON: %v\noutput:\n%s", err, out)
+	}
+
+	want := counts{Sites: 2, WithContext: 2, PropagatesContext: 1}
+	if stats.Checkers["iterator"] != want {
+		t.Errorf("checkers[iterator] = %+v, want %+v", stats.Checkers["iterator"], want)
+	}
+	if got := stats.APIs["example.com/graph/parallel.Each"]; got != want {
+		t.Errorf("apis[example.com/graph/parallel.Each] = %+v, want %+v", got, want)
+	}
+}
+
+func TestE2E_StatsTable(t *testing.T) {
+	testdata := filepath.Join(getE2ETestdata(), "graph")
+
+	cmd := exec.Command(binaryPath, "-stats", "./...")
+	cmd.Dir = testdata
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("unexpected error: %v\noutput:\n%s", err, out)
+	}
+
+	output := string(out)
+
+	for _, want := range []string{"packages: 1", "CHECKER", "API", "sync.WaitGroup.Go", "example.com/graph.runAsync"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in stats table, got:\n%s", want, output)
+		}
+	}
+}
+
+func TestE2E_StatsAfterFlagValue(t *testing.T) {
+	testdata := filepath.Join(getE2ETestdata(), "graph")
+
+	// The value of -goroutine-deriver must not end the flags before -stats
+	cmd := exec.Command(binaryPath, "-goroutine-deriver", "example.com/graph.derive", "-stats", "./...")
+	cmd.Dir = testdata
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("unexpected error: %v\noutput:\n%s", err, out)
+	}
+
+	if output := string(out); !strings.Contains(output, "packages: 1") {
+		t.Errorf("expected stats table, got:\n%s", output)
+	}
+}
+
+func TestE2E_StatsWithLintFlags(t *testing.T) {
+	testdata := filepath.Join(getE2ETestdata(), "graph")
+
+	// Flags only the linter uses are accepted and ignored
+	for _, flags := range [][]string{{"-stats", "-goroutine-deriver-used"}, {"-stats", "-handler"}} {
+		cmd := exec.Command(binaryPath, append(flags, "./...")...)
+		cmd.Dir = testdata
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("%v: unexpected error: %v\noutput:\n%s", flags, err, out)
+		}
+
+		if output := string(out); !strings.Contains(output, "packages: 1") {
+			t.Errorf("%v: expected stats table, got:\n%s", flags, output)
+		}
+	}
+}
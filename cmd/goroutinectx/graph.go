@@ -0,0 +1,203 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/packages"
+
+	"github.com/mpyw/goroutinectx"
+	"github.com/mpyw/goroutinectx/internal/graph"
+)
+
+// runGraph implements the "graph" subcommand and returns the exit code.
+func runGraph(args []string) int {
+	fs := newSiteFlagSet("graph", "Usage: goroutinectx graph [flags] [packages]\n\n"+
+		"Prints every goroutine spawn site of the packages as a DOT or JSON graph.")
+	format := fs.String("format", "dot", "output format: dot or json")
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	var write func(io.Writer, []graph.Site) error
+	switch *format {
+	case "dot":
+		write = graph.WriteDOT
+	case "json":
+		write = graph.WriteJSON
+	default:
+		fmt.Fprintf(os.Stderr, "goroutinectx graph: unknown format %q (want dot or json)\n", *format)
+		return 2
+	}
+
+	sites, err := loadSites(fs)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "goroutinectx graph: %v\n", err)
+		return 1
+	}
+
+	if err := write(os.Stdout, sites); err != nil {
+		fmt.Fprintf(os.Stderr, "goroutinectx graph: %v\n", err)
+		return 1
+	}
+
+	return 0
+}
+
+// runStats implements the -stats mode and returns the exit code.
+func runStats(args []string) int {
+	fs := newSiteFlagSet("stats", "Usage: goroutinectx -stats[=table|json] [flags] [packages]\n\n"+
+		"Prints spawn site counts aggregated across the packages, by checker and by spawn API.")
+	format := statsFormat("table")
+	fs.Var(&format, "stats", "print spawn site statistics in this format: table or json")
+	ignoreLintFlags(fs)
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	sites, err := loadSites(fs)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "goroutinectx -stats: %v\n", err)
+		return 1
+	}
+
+	stats := graph.NewStats(sites)
+	if format == "json" {
+		err = stats.WriteJSON(os.Stdout)
+	} else {
+		err = stats.WriteTable(os.Stdout)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "goroutinectx -stats: %v\n", err)
+		return 1
+	}
+
+	return 0
+}
+
+// hasStatsFlag reports whether the command line requests the -stats mode.
+// The arguments are parsed with the linter's flags, so the value of a flag
+// (e.g., -goroutine-deriver pkg.F) is not mistaken for a package pattern.
+func hasStatsFlag(args []string) bool {
+	fs := flag.NewFlagSet("goroutinectx", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Bool("test", false, "")
+	goroutinectx.Analyzer.Flags.VisitAll(func(f *flag.Flag) {
+		if b, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && b.IsBoolFlag() {
+			fs.Bool(f.Name, false, "")
+		} else {
+			fs.String(f.Name, "", "")
+		}
+	})
+
+	var format statsFormat
+	fs.Var(&format, "stats", "")
+
+	// Flags of the lint driver (e.g., -json) are unknown here: skip them and
+	// parse the rest, until the flags end at the first package pattern
+	for len(args) > 0 && fs.Parse(args) != nil {
+		args = fs.Args()
+	}
+
+	return format != ""
+}
+
+// statsFormat is the value of the -stats flag. A bare -stats selects the table.
+type statsFormat string
+
+func (f *statsFormat) String() string { return string(*f) }
+
+func (f *statsFormat) IsBoolFlag() bool { return true }
+
+func (f *statsFormat) Set(value string) error {
+	switch value {
+	case "true", "table":
+		*f = "table"
+	case "json":
+		*f = "json"
+	default:
+		return errors.New("want table or json")
+	}
+	return nil
+}
+
+// newSiteFlagSet creates a flag set with the spawn site collection flags.
+// Test packages are included by default, as when linting.
+func newSiteFlagSet(name, usage string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Bool("test", true, "include test packages")
+	graph.Analyzer.Flags.VisitAll(func(f *flag.Flag) {
+		fs.Var(f.Value, f.Name, f.Usage)
+	})
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "%s\n\nFlags:\n", usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// ignoreLintFlags accepts the linter's flags that do not affect spawn site
+// collection (e.g., -handler), so that -stats can be added to a lint command line.
+func ignoreLintFlags(fs *flag.FlagSet) {
+	goroutinectx.Analyzer.Flags.VisitAll(func(f *flag.Flag) {
+		if fs.Lookup(f.Name) != nil {
+			return
+		}
+		if b, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && b.IsBoolFlag() {
+			fs.Bool(f.Name, false, "linter flag, ignored by -stats")
+		} else {
+			fs.String(f.Name, "", "linter flag, ignored by -stats")
+		}
+	})
+}
+
+// loadSites loads the packages named by the remaining arguments of fs
+// and collects their spawn sites.
+func loadSites(fs *flag.FlagSet) ([]graph.Site, error) {
+	patterns := fs.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"."}
+	}
+
+	tests := fs.Lookup("test").Value.(flag.Getter).Get().(bool)
+
+	pkgs, err := packages.Load(&packages.Config{Mode: packages.LoadAllSyntax, Tests: tests}, patterns...)
+	if err != nil {
+		return nil, err
+	}
+	if packages.PrintErrors(pkgs) > 0 {
+		return nil, errors.New("failed to load packages")
+	}
+
+	result, err := checker.Analyze([]*analysis.Analyzer{graph.Analyzer}, pkgs, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Test variants of a package repeat its non-test spawn sites
+	var sites []graph.Site
+	seen := make(map[graph.Site]bool)
+	for _, act := range result.Roots {
+		if act.Err != nil {
+			return nil, fmt.Errorf("%s: %w", act.Package.PkgPath, act.Err)
+		}
+		for _, site := range act.Result.([]graph.Site) {
+			key := site
+			key.Package, key.CallsDeriver = "", nil
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			sites = append(sites, site)
+		}
+	}
+
+	return sites, nil
+}
@@ -1,12 +1,32 @@
 // Command goroutinectx is a linter that checks goroutine context propagation.
+//
+// The "graph" subcommand prints the spawn sites of the analyzed packages
+// as a DOT or JSON graph instead of linting them:
+//
+//	goroutinectx graph [-format=dot|json] [flags] [packages]
+//
+// The -stats flag prints spawn site counts aggregated across the packages,
+// by checker and by spawn API, as a table or JSON:
+//
+//	goroutinectx -stats[=table|json] [flags] [packages]
 package main
 
 import (
+	"os"
+
 	"golang.org/x/tools/go/analysis/singlechecker"
 
 	"github.com/mpyw/goroutinectx"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "graph" {
+		os.Exit(runGraph(os.Args[2:]))
+	}
+
+	if hasStatsFlag(os.Args[1:]) {
+		os.Exit(runStats(os.Args[1:]))
+	}
+
 	singlechecker.Main(goroutinectx.Analyzer)
 }
@@ -0,0 +1,8 @@
+package apm
+
+import "context"
+
+// NewGoroutineContext derives a context for a new goroutine.
+func NewGoroutineContext(ctx context.Context) context.Context {
+	return ctx
+}
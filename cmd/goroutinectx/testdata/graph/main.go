@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"example.com/graph/apm"
+	"example.com/graph/parallel"
+)
+
+func main() {
+	ctx := context.Background()
+	handle(ctx)
+	dispatch(ctx)
+	iterate(ctx)
+}
+
+// handle spawns goroutines that keep or drop the context.
+func handle(ctx context.Context) {
+	go func() {
+		ctx := apm.NewGoroutineContext(ctx)
+		fmt.Println("derived", ctx)
+	}()
+
+	go func() {
+		fmt.Println("no context")
+	}()
+
+	go worker(ctx)
+
+	//goroutinectx:ignore - fire and forget
+	go func() {
+		fmt.Println("ignored")
+	}()
+}
+
+// dispatch spawns through sync.WaitGroup.Go and a spawner.
+func dispatch(ctx context.Context) {
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		fmt.Println("waitgroup", ctx)
+	})
+	wg.Wait()
+
+	runAsync(func() {
+		fmt.Println("spawner")
+	})
+}
+
+// iterate ranges over a concurrent iterator, whose loop body runs on another goroutine.
+func iterate(ctx context.Context) {
+	for item := range parallel.Each([]string{"a", "b"}) {
+		fmt.Println(item, ctx)
+	}
+
+	seq := parallel.Each([]string{"c"})
+	for item := range seq {
+		fmt.Println(item)
+	}
+}
+
+func worker(ctx context.Context) {
+	fmt.Println("worker", ctx)
+}
+
+//goroutinectx:spawner
+func runAsync(fn func()) {
+	go fn()
+}
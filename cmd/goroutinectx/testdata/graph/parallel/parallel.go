@@ -0,0 +1,14 @@
+package parallel
+
+import "iter"
+
+// Each yields the items. It stands in for an iterator running its loop body on other goroutines.
+func Each[T any](items []T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, item := range items {
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
@@ -5,6 +5,14 @@ import (
 	"github.com/mpyw/goroutinectx/internal/registry"
 )
 
+// RegisterSpawnAPIs registers every spawn API whose callbacks run on new goroutines.
+func RegisterSpawnAPIs(reg *registry.Registry) {
+	RegisterErrgroupAPIs(reg)
+	RegisterWaitgroupAPIs(reg)
+	RegisterConcAPIs(reg)
+	RegisterGotaskAPIs(reg)
+}
+
 // RegisterErrgroupAPIs registers errgroup.Group APIs.
 func RegisterErrgroupAPIs(reg *registry.Registry) {
 	reg.Register(registry.Entry{
@@ -0,0 +1,422 @@
+// Package baseline records and suppresses known diagnostics for incremental adoption.
+package baseline
+
+import (
+	"bytes"
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/scanner"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// DefaultChecker is the checker name used for diagnostics without a category.
+const DefaultChecker = "goroutinectx"
+
+// version is the current baseline file format version.
+const version = 1
+
+// Entry is a recorded diagnostic. It does not contain positions,
+// so line shifts do not invalidate the baseline.
+type Entry struct {
+	Package     string `json:"package"`
+	File        string `json:"file"` // Base name of the file, telling test files apart from the package they test
+	Function    string `json:"function"`
+	Checker     string `json:"checker"`
+	Fingerprint string `json:"fingerprint"`
+	Message     string `json:"message"`
+	Count       int    `json:"count"`
+}
+
+// key identifies an entry regardless of its message and count.
+type key struct {
+	pkg, file, function, checker, fingerprint string
+}
+
+func (e *Entry) key() key {
+	return key{e.Package, e.File, e.Function, e.Checker, e.Fingerprint}
+}
+
+// File is the on-disk baseline format.
+type File struct {
+	Version int     `json:"version"`
+	Entries []Entry `json:"entries"`
+}
+
+// mu serializes baseline file access between packages analyzed concurrently
+// in one process. Processes analyzing packages separately (e.g., go vet -vettool)
+// serialize writes with a lock file next to the baseline (see lockFile).
+var mu sync.Mutex
+
+// lockTimeout bounds how long a write waits for another process's lock file.
+const lockTimeout = time.Minute
+
+// Session filters the diagnostics of a single pass against a baseline file.
+type Session struct {
+	path  string
+	write bool
+	pass  *analysis.Pass
+	orig  *analysis.Pass
+	files map[string]*ast.File // Files of the pass by base name
+
+	pending  map[key]*Entry // Baseline entries of this package not yet matched
+	recorded map[key]*Entry // Diagnostics recorded in write mode
+}
+
+// NewSession loads the baseline file and returns a session for the pass.
+// In write mode, diagnostics are recorded instead of reported, and a missing
+// file is treated as empty.
+//
+// Only the entries of the files in the pass are considered: a package and
+// its test variant share the package path, but the package alone does not
+// see the entries of its _test.go files.
+func NewSession(pass *analysis.Pass, path string, write bool) (*Session, error) {
+	s := &Session{
+		path:     path,
+		write:    write,
+		orig:     pass,
+		files:    make(map[string]*ast.File),
+		pending:  make(map[key]*Entry),
+		recorded: make(map[key]*Entry),
+	}
+
+	for _, f := range pass.Files {
+		s.files[filepath.Base(pass.Fset.Position(f.FileStart).Filename)] = f
+	}
+
+	if !write {
+		mu.Lock()
+		f, err := load(path)
+		mu.Unlock()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, e := range f.Entries {
+			if !s.owns(&e) {
+				continue
+			}
+			if pending, ok := s.pending[e.key()]; ok {
+				pending.Count += e.Count
+			} else {
+				s.pending[e.key()] = &e
+			}
+		}
+	}
+
+	filtered := *pass
+	filtered.Report = s.report
+	s.pass = &filtered
+
+	return s, nil
+}
+
+// Pass returns the pass whose Report is filtered by the baseline.
+func (s *Session) Pass() *analysis.Pass {
+	return s.pass
+}
+
+// report suppresses diagnostics that match the baseline (or records them in write mode).
+func (s *Session) report(d analysis.Diagnostic) {
+	e := s.entryOf(d)
+
+	if s.write {
+		if recorded, ok := s.recorded[e.key()]; ok {
+			recorded.Count++
+		} else {
+			s.recorded[e.key()] = &e
+		}
+		return
+	}
+
+	if pending, ok := s.pending[e.key()]; ok && pending.Count > 0 {
+		pending.Count--
+		return
+	}
+
+	s.orig.Report(d)
+}
+
+// owns reports whether the entry belongs to a file of the pass.
+func (s *Session) owns(e *Entry) bool {
+	return e.Package == s.orig.Pkg.Path() && s.files[e.File] != nil
+}
+
+// Finish writes the recorded entries in write mode, or reports baseline
+// entries of the files of this pass that no longer occur.
+func (s *Session) Finish() error {
+	if s.write {
+		return s.save()
+	}
+
+	var stale []*Entry
+	for _, e := range s.pending {
+		if e.Count > 0 {
+			stale = append(stale, e)
+		}
+	}
+	if len(stale) == 0 {
+		return nil
+	}
+
+	slices.SortFunc(stale, compareEntries)
+
+	for _, e := range stale {
+		s.orig.Report(analysis.Diagnostic{
+			Pos:      s.files[e.File].Package,
+			Category: "baseline",
+			Message:  fmt.Sprintf("baseline entry no longer occurs: %s in %s: %s", e.Checker, e.Function, e.Message),
+		})
+	}
+
+	return nil
+}
+
+// save replaces the entries of the files of this pass in the baseline file.
+// The file is read, updated and replaced under the lock file, so packages
+// written by concurrent processes do not overwrite each other's entries.
+// Entries are replaced by file, so a package and its test variant both keep
+// the entries of the files only the test variant sees.
+func (s *Session) save() error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	unlock, err := lockFile(s.path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	f, err := load(s.path)
+	if err != nil {
+		return err
+	}
+
+	f.Entries = slices.DeleteFunc(f.Entries, func(e Entry) bool {
+		return s.owns(&e)
+	})
+	for _, e := range s.recorded {
+		f.Entries = append(f.Entries, *e)
+	}
+	slices.SortFunc(f.Entries, func(a, b Entry) int {
+		return compareEntries(&a, &b)
+	})
+
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeFile(s.path, append(data, '\n'))
+}
+
+// lockFile acquires the lock file of the baseline at path, waiting up to
+// lockTimeout while another process holds it. It returns the function releasing it.
+func lockFile(path string) (func(), error) {
+	lock := path + ".lock"
+	deadline := time.Now().Add(lockTimeout)
+
+	for {
+		f, err := os.OpenFile(lock, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err == nil {
+			_ = f.Close()
+			return func() { _ = os.Remove(lock) }, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, err
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("baseline %s is locked by another process; remove %s if it is stale", path, lock)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// writeFile replaces the file at path through a temporary file, so concurrent
+// readers never see a partially written baseline.
+func writeFile(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0o644); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// entryOf builds the baseline entry for a diagnostic.
+func (s *Session) entryOf(d analysis.Diagnostic) Entry {
+	checker := d.Category
+	if checker == "" {
+		checker = DefaultChecker
+	}
+
+	file := s.fileOf(d.Pos)
+
+	var name string
+	if file != nil {
+		name = filepath.Base(s.orig.Fset.Position(file.FileStart).Filename)
+	}
+
+	return Entry{
+		Package:     s.orig.Pkg.Path(),
+		File:        name,
+		Function:    enclosingFunc(file, d.Pos),
+		Checker:     checker,
+		Fingerprint: fingerprint(checker, d.Message, s.lineText(d.Pos)),
+		Message:     d.Message,
+		Count:       1,
+	}
+}
+
+// fileOf returns the file containing pos.
+func (s *Session) fileOf(pos token.Pos) *ast.File {
+	for _, f := range s.orig.Files {
+		if f.FileStart <= pos && pos <= f.FileEnd {
+			return f
+		}
+	}
+	return nil
+}
+
+// lineText returns the tokens of the source line at pos, or empty if unavailable.
+// Comments and whitespace are dropped, so only code changes affect the fingerprint.
+func (s *Session) lineText(pos token.Pos) string {
+	if s.orig.ReadFile == nil || !pos.IsValid() {
+		return ""
+	}
+
+	position := s.orig.Fset.Position(pos)
+	content, err := s.orig.ReadFile(position.Filename)
+	if err != nil {
+		return ""
+	}
+
+	lines := bytes.Split(content, []byte("\n"))
+	if position.Line < 1 || position.Line > len(lines) {
+		return ""
+	}
+	line := lines[position.Line-1]
+
+	fset := token.NewFileSet()
+	var sc scanner.Scanner
+	sc.Init(fset.AddFile("", -1, len(line)), line, nil, 0)
+
+	var toks []string
+	for {
+		_, tok, lit := sc.Scan()
+		if tok == token.EOF {
+			break
+		}
+		if tok == token.SEMICOLON && lit == "\n" {
+			continue // Automatically inserted semicolon
+		}
+		if lit == "" {
+			lit = tok.String()
+		}
+		toks = append(toks, lit)
+	}
+
+	return strings.Join(toks, " ")
+}
+
+// load reads a baseline file. A missing file is treated as empty.
+func load(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &File{Version: version}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var f File
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("parse baseline %s: %w", path, err)
+	}
+	f.Version = version
+
+	return &f, nil
+}
+
+// enclosingFunc returns the name of the top-level function containing pos,
+// qualified with its receiver type for methods (e.g., "(*Server).Handle").
+// Returns empty string for package-level code.
+func enclosingFunc(file *ast.File, pos token.Pos) string {
+	if file == nil {
+		return ""
+	}
+
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || pos < fn.Pos() || fn.End() < pos {
+			continue
+		}
+
+		if fn.Recv == nil || len(fn.Recv.List) == 0 {
+			return fn.Name.Name
+		}
+
+		return "(" + recvTypeName(fn.Recv.List[0].Type) + ")." + fn.Name.Name
+	}
+
+	return ""
+}
+
+// recvTypeName formats a receiver type without type parameters.
+func recvTypeName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return "*" + recvTypeName(t.X)
+	case *ast.IndexExpr:
+		return recvTypeName(t.X)
+	case *ast.IndexListExpr:
+		return recvTypeName(t.X)
+	case *ast.Ident:
+		return t.Name
+	}
+	return ""
+}
+
+// fingerprint hashes the position-independent parts of a diagnostic.
+func fingerprint(checker, message, line string) string {
+	sum := sha256.Sum256([]byte(checker + "\x00" + message + "\x00" + line))
+	return hex.EncodeToString(sum[:8])
+}
+
+// compareEntries orders entries for stable output.
+func compareEntries(a, b *Entry) int {
+	return cmp.Or(
+		cmp.Compare(a.Package, b.Package),
+		cmp.Compare(a.File, b.File),
+		cmp.Compare(a.Function, b.Function),
+		cmp.Compare(a.Checker, b.Checker),
+		cmp.Compare(a.Message, b.Message),
+		cmp.Compare(a.Fingerprint, b.Fingerprint),
+	)
+}
@@ -0,0 +1,113 @@
+package baseline
+
+import (
+	"go/ast"
+	"go/types"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+func TestLockFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "baseline.json")
+
+	unlock, err := lockFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	acquired := make(chan struct{})
+	go func() {
+		unlock, err := lockFile(path)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		unlock()
+		close(acquired)
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("lock acquired while held")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	unlock()
+
+	select {
+	case <-acquired:
+	case <-time.After(5 * time.Second):
+		t.Fatal("lock not acquired after release")
+	}
+
+	if _, err := os.Stat(path + ".lock"); !os.IsNotExist(err) {
+		t.Errorf("lock file remains after release: %v", err)
+	}
+}
+
+func TestSaveKeepsOtherPackages(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "baseline.json")
+
+	for _, pkg := range []string{"example.com/a", "example.com/b"} {
+		e := &Entry{Package: pkg, Function: "F", Checker: DefaultChecker, Fingerprint: "0", Message: "m", Count: 1}
+		s := &Session{
+			path:     path,
+			write:    true,
+			orig:     &analysis.Pass{Pkg: types.NewPackage(pkg, filepath.Base(pkg))},
+			recorded: map[key]*Entry{e.key(): e},
+		}
+		if err := s.save(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	f, err := load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f.Entries) != 2 {
+		t.Errorf("got %d entries, want 2: %+v", len(f.Entries), f.Entries)
+	}
+}
+
+func TestSaveKeepsOtherFiles(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	pkg := types.NewPackage("example.com/a", "a")
+
+	// The test variant is written first; the package alone must not drop a_test.go
+	for _, files := range [][]string{{"a.go", "a_test.go"}, {"a.go"}} {
+		s := &Session{
+			path:     path,
+			write:    true,
+			orig:     &analysis.Pass{Pkg: pkg},
+			files:    make(map[string]*ast.File),
+			recorded: make(map[key]*Entry),
+		}
+		for _, file := range files {
+			s.files[file] = &ast.File{}
+			e := &Entry{Package: pkg.Path(), File: file, Function: "F", Checker: DefaultChecker, Fingerprint: "0", Message: "m", Count: 1}
+			s.recorded[e.key()] = e
+		}
+		if err := s.save(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	f, err := load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f.Entries) != 2 || f.Entries[0].File != "a.go" || f.Entries[1].File != "a_test.go" {
+		t.Errorf("got %+v, want one entry for a.go and one for a_test.go", f.Entries)
+	}
+}
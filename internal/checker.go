@@ -1,7 +1,9 @@
 package internal
 
 import (
+	"fmt"
 	"go/ast"
+	"go/token"
 
 	"golang.org/x/tools/go/analysis"
 
@@ -31,11 +33,21 @@ type CallChecker interface {
 	CheckCall(cctx *probe.Context, call *ast.CallExpr) *Result
 }
 
+// RangeStmtChecker checks range-over-func loops (for x := range seq).
+type RangeStmtChecker interface {
+	Checker
+	// MatchRange returns true if this checker should handle the range statement.
+	MatchRange(pass *analysis.Pass, stmt *ast.RangeStmt) bool
+	// CheckRangeStmt checks the range statement.
+	CheckRangeStmt(cctx *probe.Context, stmt *ast.RangeStmt) *Result
+}
+
 // Result represents the outcome of a check.
 type Result struct {
-	OK       bool   // Check passed
-	Message  string // Error message if not OK
-	DeferMsg string // Alternative message if only defer has the check
+	OK       bool      // Check passed
+	Message  string    // Error message if not OK
+	DeferMsg string    // Alternative message if only defer has the check
+	Pos      token.Pos // Position to report at instead of the checked node, if valid
 }
 
 // OK returns a passing result.
@@ -48,7 +60,22 @@ func Fail(msg string) *Result {
 	return &Result{OK: false, Message: msg}
 }
 
+// FailAt returns a failing result reported at pos instead of the checked node.
+func FailAt(pos token.Pos, msg string) *Result {
+	return &Result{OK: false, Message: msg, Pos: pos}
+}
+
 // FailWithDefer returns a failing result with defer-specific message.
 func FailWithDefer(msg, deferMsg string) *Result {
 	return &Result{OK: false, Message: msg, DeferMsg: deferMsg}
 }
+
+// Report reports a diagnostic categorized by the checker name, for checkers
+// that walk the package themselves instead of returning a Result.
+func Report(pass *analysis.Pass, checker ignore.CheckerName, pos token.Pos, format string, args ...any) {
+	pass.Report(analysis.Diagnostic{
+		Pos:      pos,
+		Category: string(checker),
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
@@ -0,0 +1,210 @@
+package checkers
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"slices"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/astutil"
+
+	"github.com/mpyw/goroutinectx/internal"
+	"github.com/mpyw/goroutinectx/internal/directive/ignore"
+	"github.com/mpyw/goroutinectx/internal/funcspec"
+	"github.com/mpyw/goroutinectx/internal/probe"
+)
+
+var (
+	// semaphoreAcquire blocks until the weight is available or its ctx is done.
+	semaphoreAcquire = funcspec.Spec{PkgPath: "golang.org/x/sync/semaphore", TypeName: "Weighted", FuncName: "Acquire"}
+
+	// errgroupGo blocks once the group's SetLimit is reached.
+	errgroupGo = funcspec.Spec{PkgPath: "golang.org/x/sync/errgroup", TypeName: "Group", FuncName: "Go"}
+
+	// errgroupSetLimit turns errgroupGo into a blocking admission call.
+	errgroupSetLimit = funcspec.Spec{PkgPath: "golang.org/x/sync/errgroup", TypeName: "Group", FuncName: "SetLimit"}
+)
+
+// Admission checks that blocking spawn admission respects context cancellation.
+// It implements both GoStmtChecker (channel-based semaphores) and
+// CallChecker (semaphore.Weighted.Acquire, limited errgroup.Group.Go).
+type Admission struct{}
+
+// Name returns the checker name for ignore directive matching.
+func (*Admission) Name() ignore.CheckerName {
+	return ignore.Admission
+}
+
+// CheckGoStmt checks whether a go statement is admitted by a blocking channel send.
+func (*Admission) CheckGoStmt(cctx *probe.Context, stmt *ast.GoStmt) *internal.Result {
+	send := precedingSemaphoreSend(cctx, stmt)
+	if send == nil {
+		return internal.OK()
+	}
+
+	return internal.Fail(fmt.Sprintf(
+		"go statement is admitted by a blocking send to %q; select on %s.Done() so spawn admission respects cancellation",
+		types.ExprString(send.Chan), ctxNameOf(cctx),
+	))
+}
+
+// MatchCall returns true if this checker should handle the call.
+func (*Admission) MatchCall(pass *analysis.Pass, call *ast.CallExpr) bool {
+	fn := funcspec.ExtractFunc(pass, call)
+	if fn == nil {
+		return false
+	}
+	return semaphoreAcquire.Matches(fn) || errgroupGo.Matches(fn)
+}
+
+// CheckCall checks the call expression.
+func (*Admission) CheckCall(cctx *probe.Context, call *ast.CallExpr) *internal.Result {
+	fn := funcspec.ExtractFunc(cctx.Pass, call)
+	if fn == nil {
+		return internal.OK()
+	}
+
+	switch {
+	case semaphoreAcquire.Matches(fn):
+		if len(call.Args) == 0 || cctx.ArgUsesContext(call.Args[0]) {
+			return internal.OK()
+		}
+		return internal.Fail(fmt.Sprintf(
+			"%s() should use context %q so spawn admission respects cancellation (or use TryAcquire)",
+			semaphoreAcquire.FullName(), ctxNameOf(cctx),
+		))
+
+	case errgroupGo.Matches(fn):
+		if !receiverHasLimit(cctx, call) {
+			return internal.OK()
+		}
+		return internal.Fail(fmt.Sprintf(
+			"%s() blocks once SetLimit is reached and ignores context %q; use TryGo so spawn admission respects cancellation",
+			errgroupGo.FullName(), ctxNameOf(cctx),
+		))
+	}
+
+	return internal.OK()
+}
+
+// receiverHasLimit checks if SetLimit is called on the errgroup receiver before
+// the Go call on every path through the same function.
+func receiverHasLimit(cctx *probe.Context, call *ast.CallExpr) bool {
+	if cctx.SSAProg == nil || cctx.Tracer == nil {
+		return false
+	}
+
+	return cctx.Tracer.ReceiverCalledBefore(cctx.SSAProg.FindCall(call), errgroupSetLimit)
+}
+
+// precedingSemaphoreSend finds a send to a struct{} channel that precedes the go
+// statement in the same statement list, on the channel the spawned func literal
+// releases with a deferred receive. Sends released again before the go statement
+// do not admit it.
+func precedingSemaphoreSend(cctx *probe.Context, stmt *ast.GoStmt) *ast.SendStmt {
+	lit, ok := stmt.Call.Fun.(*ast.FuncLit)
+	if !ok {
+		return nil
+	}
+
+	released := deferredReceives(lit)
+	if len(released) == 0 {
+		return nil
+	}
+
+	f := cctx.FileOf(stmt.Pos())
+	if f == nil {
+		return nil
+	}
+
+	path, _ := astutil.PathEnclosingInterval(f, stmt.Pos(), stmt.End())
+	if len(path) < 2 {
+		return nil
+	}
+
+	var list []ast.Stmt
+	switch parent := path[1].(type) {
+	case *ast.BlockStmt:
+		list = parent.List
+	case *ast.CaseClause:
+		list = parent.Body
+	case *ast.CommClause:
+		list = parent.Body
+	default:
+		return nil
+	}
+
+	i := slices.Index(list, ast.Stmt(stmt))
+	for _, prev := range slices.Backward(list[:max(i, 0)]) {
+		switch prev := prev.(type) {
+		case *ast.SendStmt:
+			if isSemaphoreChan(cctx.Pass, prev.Chan) && slices.ContainsFunc(released, func(ch ast.Expr) bool {
+				return sameChan(cctx.Pass, ch, prev.Chan)
+			}) {
+				return prev
+			}
+		case *ast.ExprStmt:
+			recv, ok := ast.Unparen(prev.X).(*ast.UnaryExpr)
+			if ok && recv.Op == token.ARROW && slices.ContainsFunc(released, func(ch ast.Expr) bool {
+				return sameChan(cctx.Pass, ch, recv.X)
+			}) {
+				return nil // Already released before the go statement
+			}
+		}
+	}
+
+	return nil
+}
+
+// deferredReceives returns the channels a func literal receives from in its defer statements.
+func deferredReceives(lit *ast.FuncLit) []ast.Expr {
+	var chans []ast.Expr
+
+	ast.Inspect(lit.Body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false // Defers of nested funcs run when they return
+		case *ast.DeferStmt:
+			ast.Inspect(n.Call, func(n ast.Node) bool {
+				if recv, ok := n.(*ast.UnaryExpr); ok && recv.Op == token.ARROW {
+					chans = append(chans, recv.X)
+				}
+				return true
+			})
+			return false
+		}
+		return true
+	})
+
+	return chans
+}
+
+// sameChan checks if two expressions denote the same channel variable.
+func sameChan(pass *analysis.Pass, a, b ast.Expr) bool {
+	a, b = ast.Unparen(a), ast.Unparen(b)
+
+	if identA, ok := a.(*ast.Ident); ok {
+		identB, ok := b.(*ast.Ident)
+		return ok && pass.TypesInfo.ObjectOf(identA) != nil && pass.TypesInfo.ObjectOf(identA) == pass.TypesInfo.ObjectOf(identB)
+	}
+
+	return types.ExprString(a) == types.ExprString(b)
+}
+
+// isSemaphoreChan checks if the expression is a channel of empty structs.
+func isSemaphoreChan(pass *analysis.Pass, expr ast.Expr) bool {
+	typ := pass.TypesInfo.TypeOf(expr)
+	if typ == nil {
+		return false
+	}
+
+	ch, ok := typ.Underlying().(*types.Chan)
+	if !ok {
+		return false
+	}
+
+	st, ok := ch.Elem().Underlying().(*types.Struct)
+	return ok && st.NumFields() == 0
+}
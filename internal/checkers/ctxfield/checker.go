@@ -0,0 +1,155 @@
+package ctxfield
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/mpyw/goroutinectx/internal"
+	"github.com/mpyw/goroutinectx/internal/directive/carrier"
+	"github.com/mpyw/goroutinectx/internal/directive/ignore"
+	"github.com/mpyw/goroutinectx/internal/funcspec"
+	"github.com/mpyw/goroutinectx/internal/registry"
+	"github.com/mpyw/goroutinectx/internal/typeutil"
+)
+
+const checkerName = ignore.Ctxfield
+
+// Checker reports values of struct types storing a context.Context that are
+// captured by goroutines or passed to spawn APIs.
+type Checker struct {
+	registry *registry.Registry
+	allowed  []carrier.Carrier
+	carriers []carrier.Carrier
+}
+
+// New creates a new ctxfield checker.
+// Struct types matching allowed or carriers are never reported.
+func New(reg *registry.Registry, allowed, carriers []carrier.Carrier) *Checker {
+	return &Checker{registry: reg, allowed: allowed, carriers: carriers}
+}
+
+// Check runs the ctxfield analysis on the given pass.
+func (c *Checker) Check(pass *analysis.Pass, ignoreMaps map[string]ignore.Map, skipFiles map[string]bool) {
+	for _, file := range pass.Files {
+		filename := pass.Fset.Position(file.Pos()).Filename
+		if skipFiles[filename] {
+			continue
+		}
+		ignoreMap := ignoreMaps[filename]
+
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch node := n.(type) {
+			case *ast.GoStmt:
+				c.checkSpawn(pass, node.Pos(), "goroutine", goSpawned(node.Call), ignoreMap)
+
+			case *ast.CallExpr:
+				fn := funcspec.ExtractFunc(pass, node)
+				if fn == nil {
+					return true
+				}
+				match := c.registry.MatchFunc(fn)
+				if match == nil || match.AlwaysSpawns || match.CallbackArgIdx >= len(node.Args) {
+					return true
+				}
+				c.checkSpawn(pass, node.Pos(), match.FullName+"() closure", node.Args[match.CallbackArgIdx:], ignoreMap)
+			}
+			return true
+		})
+	}
+}
+
+// goSpawned returns the expressions whose values reach the goroutine of a go statement:
+// the func literal, or the called function (including a method receiver) and its arguments.
+func goSpawned(call *ast.CallExpr) []ast.Expr {
+	if lit, ok := call.Fun.(*ast.FuncLit); ok {
+		return []ast.Expr{lit}
+	}
+	return append([]ast.Expr{call.Fun}, call.Args...)
+}
+
+// checkSpawn reports the first variable of a context-storing struct type
+// used by the spawned expressions.
+func (c *Checker) checkSpawn(pass *analysis.Pass, pos token.Pos, subject string, spawned []ast.Expr, ignoreMap ignore.Map) {
+	for _, expr := range spawned {
+		v, field := c.findStored(pass, expr)
+		if v == nil {
+			continue
+		}
+
+		line := pass.Fset.Position(pos).Line
+		if ignoreMap.ShouldIgnore(line, checkerName) {
+			return
+		}
+
+		typeName := types.TypeString(typeutil.UnwrapPointer(v.Type()), types.RelativeTo(pass.Pkg))
+		internal.Report(pass, checkerName, pos,
+			"%s captures %q of type %s, which stores a context.Context in field %q; pass the context explicitly instead",
+			subject, v.Name(), typeName, field)
+		return
+	}
+}
+
+// findStored returns the first variable of a context-storing struct type used by expr,
+// and the name of its context field. Variables declared inside a func literal are
+// not captured and are skipped. Field and method selectors are not variables of their own.
+func (c *Checker) findStored(pass *analysis.Pass, expr ast.Expr) (*types.Var, string) {
+	lit, _ := ast.Unparen(expr).(*ast.FuncLit)
+
+	var (
+		found *types.Var
+		field string
+	)
+
+	var visit func(n ast.Node) bool
+	visit = func(n ast.Node) bool {
+		if found != nil {
+			return false
+		}
+
+		switch node := n.(type) {
+		case *ast.SelectorExpr:
+			ast.Inspect(node.X, visit)
+			return false
+
+		case *ast.Ident:
+			v, ok := pass.TypesInfo.Uses[node].(*types.Var)
+			if !ok || v.IsField() {
+				return true
+			}
+			if lit != nil && lit.Pos() <= v.Pos() && v.Pos() < lit.End() {
+				return true
+			}
+			if name := c.storedField(v.Type()); name != "" {
+				found, field = v, name
+			}
+		}
+		return true
+	}
+	ast.Inspect(expr, visit)
+
+	return found, field
+}
+
+// storedField returns the name of the first context.Context field of a struct type
+// (or pointer to one), or empty string if it has none or is allow-listed.
+func (c *Checker) storedField(t types.Type) string {
+	if typeutil.IsContextType(t) || carrier.IsCarrierType(t, c.carriers) || carrier.IsCarrierType(t, c.allowed) {
+		return ""
+	}
+
+	st, ok := typeutil.UnwrapPointer(t).Underlying().(*types.Struct)
+	if !ok {
+		return ""
+	}
+
+	for field := range st.Fields() {
+		if typeutil.IsContextType(field.Type()) {
+			return field.Name()
+		}
+	}
+
+	return ""
+}
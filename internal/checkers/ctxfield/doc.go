@@ -0,0 +1,41 @@
+// Package ctxfield detects structs storing a context.Context that cross goroutine boundaries.
+//
+// # Overview
+//
+// The context package documentation asks not to store contexts in structs.
+// A struct that stashes a request context and is handed to a background
+// worker keeps the request context alive and couples the worker to its
+// cancellation:
+//
+//	type job struct {
+//	    ctx  context.Context
+//	    name string
+//	}
+//
+//	func enqueue(j *job) {
+//	    go func() {
+//	        run(j)  // Warning: j stores a context.Context in field "ctx"
+//	    }()
+//	}
+//
+// # Spawns
+//
+// A value is reported when a variable of a struct type (or pointer to one)
+// with a context.Context field is:
+//
+//   - Captured by a go statement's func literal, or passed as its receiver or argument
+//   - Captured by or passed to a registered spawn API callback (errgroup,
+//     sync.WaitGroup, conc)
+//
+// Variables declared inside the spawned func literal are not captured and are
+// not reported. Types listed in -ctxfield-allow and -context-carriers are
+// never reported.
+//
+// # Integration
+//
+// Like handler, the checker operates at the pass level rather than per node,
+// since the spawning function need not have a context parameter:
+//
+//	ctxfieldChecker := ctxfield.New(reg, allowed, carriers)
+//	ctxfieldChecker.Check(pass, ignoreMaps, skipFiles)
+package ctxfield
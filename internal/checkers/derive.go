@@ -0,0 +1,57 @@
+package checkers
+
+import (
+	"fmt"
+	"go/ast"
+
+	gossa "golang.org/x/tools/go/ssa"
+
+	"github.com/mpyw/goroutinectx/internal"
+	"github.com/mpyw/goroutinectx/internal/deriver"
+	"github.com/mpyw/goroutinectx/internal/probe"
+	"github.com/mpyw/goroutinectx/internal/ssa"
+)
+
+// checkDerivedUse checks how a spawned closure that calls a deriver at its start
+// uses the derived context. The captured context must not be used before the
+// deriver call or instead of its result and, if requireUsed, the derived context
+// must not be discarded. subject names the closure in messages (e.g., "goroutine").
+// The failure is reported at the offending use. Returns nil if the closure
+// derives properly or calls no deriver at its start.
+func checkDerivedUse(cctx *probe.Context, subject string, fn *gossa.Function, derivers *deriver.Matcher, requireUsed bool) *internal.Result {
+	if fn == nil || cctx.Tracer == nil || derivers == nil || derivers.IsEmpty() {
+		return nil
+	}
+
+	if !cctx.Tracer.ClosureCallsDeriver(fn, derivers).FoundAtStart {
+		return nil
+	}
+
+	if use := cctx.Tracer.FirstOriginalContextUse(fn, derivers); use != nil {
+		if use.After {
+			return internal.FailAt(use.Pos, fmt.Sprintf("%s uses the original context %q after calling %s; use the derived context instead", subject, use.Name, derivers.Original))
+		}
+		return internal.FailAt(use.Pos, fmt.Sprintf("%s uses context %q before calling %s to derive it", subject, use.Name, derivers.Original))
+	}
+
+	if requireUsed {
+		if call := cctx.Tracer.FirstDiscardedDeriverCall(fn, derivers); call != nil {
+			name := derivers.Original
+			if calledFn := ssa.ExtractCalledFunc(&call.Call); calledFn != nil {
+				name = calledFn.FullName()
+			}
+			return internal.FailAt(call.Pos(), fmt.Sprintf("%s discards the context derived by %s; pass it to subsequent calls", subject, name))
+		}
+	}
+
+	return nil
+}
+
+// checkDerivedUseOfLit is checkDerivedUse for a spawned func literal.
+// Literals intentionally detaching from the context are skipped.
+func checkDerivedUseOfLit(cctx *probe.Context, subject string, lit *ast.FuncLit, derivers *deriver.Matcher, requireUsed bool) *internal.Result {
+	if cctx.SSAProg == nil || cctx.FuncLitUsesDetachedContext(lit) {
+		return nil
+	}
+	return checkDerivedUse(cctx, subject, cctx.SSAProg.FindFuncLit(lit), derivers, requireUsed)
+}
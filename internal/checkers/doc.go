@@ -18,6 +18,14 @@
 //	│    - Conc            │ github.com/sourcegraph/conc callbacks        │
 //	│  - SpawnerChecker    │ //goroutinectx:spawner marked functions      │
 //	│  - GotaskChecker     │ gotask library functions                     │
+//	│  - Stdlib            │ time.AfterFunc, context.AfterFunc, ...       │
+//	├──────────────────────┼──────────────────────────────────────────────┤
+//	│ RangeStmtChecker     │ Checks range-over-func loops                 │
+//	│  - IteratorChecker   │ -concurrent-iterator loop bodies             │
+//	├──────────────────────┼──────────────────────────────────────────────┤
+//	│ Both                 │                                              │
+//	│  - Admission         │ semaphores / limited errgroups ignoring ctx  │
+//	│  - LoopCancel        │ per-iteration ctx with function-exit cancel  │
 //	└──────────────────────┴──────────────────────────────────────────────┘
 //
 // # GoStmtChecker
@@ -42,9 +50,9 @@
 //
 // Factory functions create checkers for specific APIs:
 //
-//	checker := NewErrgroupChecker(deriveMatcher)
-//	checker := NewWaitgroupChecker(deriveMatcher)
-//	checker := NewConcChecker(deriveMatcher)
+//	checker := NewErrgroupChecker(deriverRules, requireUsed)
+//	checker := NewWaitgroupChecker(deriverRules, requireUsed)
+//	checker := NewConcChecker(deriverRules, requireUsed)
 //
 // Example detection:
 //
@@ -68,6 +76,71 @@
 //	    }()
 //	}
 //
+// Every use of the captured context must be dominated by a deriver call in the
+// SSA control flow graph, and the derived value must be used afterwards. The
+// first use breaking either rule is reported at the use. The same check applies
+// to spawn API closures, spawner func arguments, gotask tasks and concurrent
+// iterator loop bodies that call a deriver:
+//
+//	go func() {
+//	    doWork(ctx)                          // <- Warning: used before deriving
+//	    ctx := apm.NewGoroutineContext(ctx)
+//	    doWork(ctx)
+//	}()
+//
+// With -goroutine-deriver-used, a deriver call whose returned context is
+// discarded instead of passed to subsequent calls is reported as well.
+//
+// # Admission
+//
+// When enabled with -admission flag, checks that blocking spawn admission
+// respects context cancellation:
+//
+//	func worker(ctx context.Context) {
+//	    _ = sem.Acquire(context.Background(), 1)  // <- Warning: should use ctx
+//	    sem <- struct{}{}                         // <- Warning reported at the go statement
+//	    go func() { ... }()
+//	}
+//
+// # Stdlib
+//
+// Checks callbacks of standard library APIs with per-API rules: time.AfterFunc
+// and http.Server.RegisterOnShutdown callbacks should use ctx, while
+// runtime.SetFinalizer and testing.T.Cleanup callbacks run once the context is
+// likely done and must not capture it. context.AfterFunc callbacks run once the
+// registered context is done, so they must not pass it or its derivatives
+// downstream, nor capture it; contexts detached before registering with
+// context.WithoutCancel or -detach-funcs are accepted.
+// sync.Once.Do runs synchronously and is never reported:
+//
+//	func worker(ctx context.Context) {
+//	    context.AfterFunc(ctx, func() {
+//	        cleanup(ctx)  // <- Warning: ctx is already done
+//	    })
+//	    detached := context.WithoutCancel(ctx)
+//	    context.AfterFunc(ctx, func() {
+//	        cleanup(detached)  // OK
+//	    })
+//	}
+//
+// # LoopCancel
+//
+// Checks goroutines and spawn API closures created in a loop that use a
+// context derived in the same loop iteration (context.WithCancel, WithTimeout,
+// WithDeadline and their Cause variants) whose cancel is only deferred in the
+// enclosing function. Loops are found as cycles of the SSA control flow graph:
+//
+//	for _, item := range items {
+//	    tctx, cancel := context.WithTimeout(ctx, time.Second)
+//	    defer cancel()  // <- Runs at function exit, not per iteration
+//	    go func() {     // <- Warning
+//	        process(tctx, item)
+//	    }()
+//	}
+//
+// Calling cancel in the goroutine, in the iteration, or passing it to
+// another function is accepted.
+//
 // # Gotask Checker
 //
 // Checks gotask library usage for proper context derivation:
@@ -26,7 +26,7 @@ func (c *Goroutine) CheckGoStmt(cctx *probe.Context, stmt *ast.GoStmt) *internal
 	// Try SSA-based check first
 	if lit, ok := stmt.Call.Fun.(*ast.FuncLit); ok {
 		if result, ok := cctx.FuncLitCapturesContextSSA(lit); ok {
-			if result {
+			if result || cctx.FuncLitUsesDetachedContext(lit) {
 				return internal.OK()
 			}
 			return internal.Fail(c.message(cctx))
@@ -53,7 +53,7 @@ func (*Goroutine) checkFromAST(cctx *probe.Context, stmt *ast.GoStmt) bool {
 	call := stmt.Call
 
 	if lit, ok := call.Fun.(*ast.FuncLit); ok {
-		return cctx.FuncLitCapturesContext(lit)
+		return cctx.FuncLitCapturesContext(lit) || cctx.FuncLitUsesDetachedContext(lit)
 	}
 
 	if innerCall, ok := call.Fun.(*ast.CallExpr); ok {
@@ -81,12 +81,14 @@ func (*Goroutine) checkFromAST(cctx *probe.Context, stmt *ast.GoStmt) bool {
 
 // GoroutineDerive checks that go statements call a deriver function.
 type GoroutineDerive struct {
-	derivers *deriver.Matcher
+	derivers    *deriver.Matcher
+	requireUsed bool // Report deriver calls whose derived context is discarded
 }
 
 // NewGoroutineDerive creates a new GoroutineDerive checker.
-func NewGoroutineDerive(derivers *deriver.Matcher) *GoroutineDerive {
-	return &GoroutineDerive{derivers: derivers}
+// If requireUsed is true, the derived context must also be used.
+func NewGoroutineDerive(derivers *deriver.Matcher, requireUsed bool) *GoroutineDerive {
+	return &GoroutineDerive{derivers: derivers, requireUsed: requireUsed}
 }
 
 // Name returns the checker name for ignore directive matching.
@@ -103,7 +105,7 @@ func (c *GoroutineDerive) CheckGoStmt(cctx *probe.Context, stmt *ast.GoStmt) *in
 	call := stmt.Call
 
 	if lit, ok := call.Fun.(*ast.FuncLit); ok {
-		if cctx.FuncLitHasContextParam(lit) {
+		if cctx.FuncLitHasContextParam(lit) || cctx.FuncLitUsesDetachedContext(lit) {
 			return internal.OK()
 		}
 
@@ -155,6 +157,9 @@ func (c *GoroutineDerive) checkFromSSA(cctx *probe.Context, lit *ast.FuncLit) (*
 	result := cctx.Tracer.ClosureCallsDeriver(ssaFn, c.derivers)
 
 	if result.FoundAtStart {
+		if misuse := checkDerivedUse(cctx, "goroutine", ssaFn, c.derivers, c.requireUsed); misuse != nil {
+			return misuse, true
+		}
 		return internal.OK(), true
 	}
 
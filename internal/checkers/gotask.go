@@ -30,8 +30,9 @@ type taskConstructorConfig struct {
 
 // GotaskChecker checks gotask library API calls.
 type GotaskChecker struct {
-	derivers *deriver.Matcher
-	entries  []gotaskEntry
+	derivers    *deriver.Matcher
+	requireUsed bool // Report deriver calls whose derived context is discarded
+	entries     []gotaskEntry
 }
 
 // gotaskEntry defines a gotask API to check.
@@ -43,13 +44,15 @@ type gotaskEntry struct {
 }
 
 // NewGotaskChecker creates a gotask checker.
-func NewGotaskChecker(derivers *deriver.Matcher) *GotaskChecker {
+// If requireUsed is true, the derived context must also be used.
+func NewGotaskChecker(derivers *deriver.Matcher, requireUsed bool) *GotaskChecker {
 	if derivers == nil {
 		return nil
 	}
 
 	return &GotaskChecker{
-		derivers: derivers,
+		derivers:    derivers,
+		requireUsed: requireUsed,
 		entries: []gotaskEntry{
 			// DoAll variants
 			{Spec: funcspec.Spec{PkgPath: "github.com/siketyan/gotask", FuncName: "DoAll"}, CallbackArgIdx: 1, Variadic: true},
@@ -127,6 +130,14 @@ func (c *GotaskChecker) checkDoAsync(cctx *probe.Context, call *ast.CallExpr, en
 		return internal.OK()
 	}
 
+	// A callback deriving its own context must use the derived one
+	if lit, ok := c.taskCallback(cctx, call).(*ast.FuncLit); ok {
+		subject := gotaskConstructor.PkgPath + "." + gotaskConstructor.FuncName + "() callback"
+		if misuse := checkDerivedUseOfLit(cctx, subject, lit, c.derivers, c.requireUsed); misuse != nil {
+			return misuse
+		}
+	}
+
 	// Check 2: Does the task's callback call the deriver?
 	if c.taskCallbackCallsDeriver(cctx, call) {
 		return internal.OK()
@@ -173,6 +184,18 @@ func (c *GotaskChecker) checkVariadic(cctx *probe.Context, call *ast.CallExpr, e
 	isVariadicExpansion := call.Ellipsis.IsValid()
 
 	for i := startIdx; i < len(call.Args); i++ {
+		if lit, ok := call.Args[i].(*ast.FuncLit); ok {
+			if captured, ignores := cctx.FuncLitIgnoresContextParam(lit); ignores {
+				cctx.Pass.Reportf(call.Pos(), "%s() %s argument should use its own context parameter instead of capturing %q",
+					entry.Spec.FullName(), ordinal(i+1), captured)
+				continue
+			}
+			subject := fmt.Sprintf("%s() %s argument", entry.Spec.FullName(), ordinal(i+1))
+			if misuse := checkDerivedUseOfLit(cctx, subject, lit, c.derivers, c.requireUsed); misuse != nil {
+				cctx.Pass.Reportf(misuse.Pos, "%s", misuse.Message)
+				continue
+			}
+		}
 		if !c.argCallsDeriver(cctx, call.Args[i], entry) {
 			var msg string
 			if isVariadicExpansion {
@@ -188,6 +211,14 @@ func (c *GotaskChecker) checkVariadic(cctx *probe.Context, call *ast.CallExpr, e
 	}
 }
 
+// ctxNameOf returns the first context name in scope for message formatting.
+func ctxNameOf(cctx *probe.Context) string {
+	if len(cctx.CtxNames) > 0 {
+		return cctx.CtxNames[0]
+	}
+	return "ctx"
+}
+
 // argIsDeriverCall checks if the argument expression IS a call to the deriver.
 func (c *GotaskChecker) argIsDeriverCall(cctx *probe.Context, expr ast.Expr) bool {
 	call, ok := expr.(*ast.CallExpr)
@@ -217,26 +248,34 @@ func (c *GotaskChecker) identIsDeriverCall(cctx *probe.Context, ident *ast.Ident
 
 // taskCallbackCallsDeriver checks if the task's callback (from constructor) calls the deriver.
 func (c *GotaskChecker) taskCallbackCallsDeriver(cctx *probe.Context, call *ast.CallExpr) bool {
+	callbackArg := c.taskCallback(cctx, call)
+	if callbackArg == nil {
+		return false
+	}
+	return c.callbackCallsDeriver(cctx, callbackArg)
+}
+
+// taskCallback returns the callback argument of the constructor that created
+// the task a DoAsync call is made on, or nil if it cannot be traced.
+func (c *GotaskChecker) taskCallback(cctx *probe.Context, call *ast.CallExpr) ast.Expr {
 	// Task is always the method receiver (e.g., task.DoAsync)
 	taskExpr := getMethodReceiver(call)
 	if taskExpr == nil {
-		return false
+		return nil
 	}
 
 	// Find the constructor call that created this task
 	constructorCall := c.findConstructorCall(cctx, taskExpr)
 	if constructorCall == nil {
-		return false
+		return nil
 	}
 
-	// Check if constructor's callback argument calls the deriver
 	argIdx := gotaskConstructor.CallbackArgIdx
 	if argIdx < 0 || argIdx >= len(constructorCall.Args) {
-		return false
+		return nil
 	}
 
-	callbackArg := constructorCall.Args[argIdx]
-	return c.callbackCallsDeriver(cctx, callbackArg)
+	return constructorCall.Args[argIdx]
 }
 
 // getMethodReceiver extracts the receiver from a method call.
@@ -0,0 +1,500 @@
+package handler
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/mpyw/goroutinectx/internal"
+	"github.com/mpyw/goroutinectx/internal/directive/carrier"
+	"github.com/mpyw/goroutinectx/internal/directive/ignore"
+	"github.com/mpyw/goroutinectx/internal/funcspec"
+	"github.com/mpyw/goroutinectx/internal/probe"
+	"github.com/mpyw/goroutinectx/internal/registry"
+	"github.com/mpyw/goroutinectx/internal/typeutil"
+)
+
+const checkerName = ignore.Handler
+
+// Checker reports goroutines that capture a request context in a handler
+// and are not awaited before the handler returns.
+type Checker struct {
+	handlers  []funcspec.Spec
+	registry  *registry.Registry
+	carriers  []carrier.Carrier
+	detachers []funcspec.Spec
+}
+
+// New creates a new handler checker.
+// Handlers are functions matching one of the specs, in addition to net/http
+// handlers and gRPC service methods. A spec naming a registration function
+// (e.g., a router method) marks its func literal arguments as handlers.
+// Arguments of the detach functions and context.WithoutCancel are never request-bound.
+func New(handlers []funcspec.Spec, reg *registry.Registry, carriers []carrier.Carrier, detachers []funcspec.Spec) *Checker {
+	return &Checker{
+		handlers:  handlers,
+		registry:  reg,
+		carriers:  carriers,
+		detachers: probe.WithWithoutCancel(detachers),
+	}
+}
+
+// handler is a function recognized as a request handler.
+type handler struct {
+	fnType *ast.FuncType
+	body   *ast.BlockStmt
+}
+
+// Check runs the handler analysis on the given pass.
+func (c *Checker) Check(pass *analysis.Pass, ignoreMaps map[string]ignore.Map, skipFiles map[string]bool) {
+	for _, file := range pass.Files {
+		filename := pass.Fset.Position(file.Pos()).Filename
+		if skipFiles[filename] {
+			continue
+		}
+		ignoreMap := ignoreMaps[filename]
+
+		for _, h := range c.findHandlers(pass, file) {
+			c.checkHandler(pass, h, ignoreMap)
+		}
+	}
+}
+
+// findHandlers returns the handlers declared in the file.
+func (c *Checker) findHandlers(pass *analysis.Pass, file *ast.File) []handler {
+	var handlers []handler
+
+	registered := make(map[*ast.FuncLit]bool)
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncDecl:
+			if node.Body != nil && c.isHandlerDecl(pass, node) {
+				handlers = append(handlers, handler{fnType: node.Type, body: node.Body})
+			}
+
+		case *ast.CallExpr:
+			// Func literals passed to a configured registration function
+			if fn := funcspec.ExtractFunc(pass, node); fn != nil && c.matchesHandlerSpec(fn) {
+				for _, arg := range node.Args {
+					if lit, ok := ast.Unparen(arg).(*ast.FuncLit); ok {
+						registered[lit] = true
+					}
+				}
+			}
+
+		case *ast.FuncLit:
+			sig, _ := pass.TypesInfo.TypeOf(node).(*types.Signature)
+			if registered[node] || isHTTPHandlerSig(sig) {
+				handlers = append(handlers, handler{fnType: node.Type, body: node.Body})
+			}
+		}
+		return true
+	})
+
+	return handlers
+}
+
+// isHandlerDecl reports whether a function declaration is a request handler.
+func (c *Checker) isHandlerDecl(pass *analysis.Pass, decl *ast.FuncDecl) bool {
+	fn, ok := pass.TypesInfo.Defs[decl.Name].(*types.Func)
+	if !ok {
+		return false
+	}
+
+	return isHTTPHandlerSig(fn.Signature()) || c.matchesHandlerSpec(fn) || isGRPCMethod(fn)
+}
+
+// matchesHandlerSpec reports whether fn matches a configured handler spec.
+func (c *Checker) matchesHandlerSpec(fn *types.Func) bool {
+	for _, spec := range c.handlers {
+		if spec.Matches(fn) {
+			return true
+		}
+	}
+	return false
+}
+
+// isHTTPHandlerSig reports whether the signature is func(http.ResponseWriter, *http.Request),
+// which covers ServeHTTP methods and http.HandlerFunc literals.
+func isHTTPHandlerSig(sig *types.Signature) bool {
+	if sig == nil || sig.Params().Len() != 2 || sig.Results().Len() != 0 {
+		return false
+	}
+
+	return isNamed(sig.Params().At(0).Type(), "net/http", "ResponseWriter") &&
+		isHTTPRequest(sig.Params().At(1).Type())
+}
+
+// isGRPCMethod reports whether fn is a gRPC unary service method: a method taking
+// a context.Context first, on a type embedding a generated Unimplemented*Server.
+func isGRPCMethod(fn *types.Func) bool {
+	sig, ok := fn.Type().(*types.Signature)
+	if !ok || sig.Recv() == nil || sig.Params().Len() != 2 || sig.Results().Len() != 2 {
+		return false
+	}
+
+	if !typeutil.IsContextType(sig.Params().At(0).Type()) {
+		return false
+	}
+
+	st, ok := typeutil.UnwrapPointer(sig.Recv().Type()).Underlying().(*types.Struct)
+	if !ok {
+		return false
+	}
+
+	for i := 0; i < st.NumFields(); i++ {
+		field := st.Field(i)
+		name := field.Name()
+		if field.Embedded() && strings.HasPrefix(name, "Unimplemented") && strings.HasSuffix(name, "Server") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isHTTPRequest reports whether t is *http.Request.
+func isHTTPRequest(t types.Type) bool {
+	ptr, ok := t.(*types.Pointer)
+	return ok && isNamed(ptr.Elem(), "net/http", "Request")
+}
+
+// isNamed reports whether t is the named type pkgPath.name.
+func isNamed(t types.Type, pkgPath, name string) bool {
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == pkgPath && obj.Name() == name
+}
+
+// checkHandler reports request-bound goroutines of a handler that are not awaited.
+func (c *Checker) checkHandler(pass *analysis.Pass, h handler, ignoreMap ignore.Map) {
+	bound := c.requestVars(pass, h)
+	if len(bound) == 0 {
+		return
+	}
+
+	c.trackDerived(pass, h.body, bound)
+
+	inspectBody(h.body, func(n ast.Node) {
+		var (
+			spawned []ast.Expr
+			handles []ast.Expr
+			subject string
+			pos     token.Pos
+		)
+
+		switch node := n.(type) {
+		case *ast.GoStmt:
+			subject, pos = "goroutine", node.Pos()
+			if lit, ok := node.Call.Fun.(*ast.FuncLit); ok {
+				spawned = []ast.Expr{lit}
+				handles = signaledHandles(pass, lit)
+			} else {
+				spawned = append([]ast.Expr{node.Call.Fun}, node.Call.Args...)
+				handles = handleArgs(pass, node.Call.Args)
+			}
+
+		case *ast.CallExpr:
+			fn := funcspec.ExtractFunc(pass, node)
+			if fn == nil || c.registry == nil {
+				return
+			}
+			match := c.registry.MatchFunc(fn)
+			if match == nil || match.AlwaysSpawns || match.CallbackArgIdx >= len(node.Args) {
+				return
+			}
+			subject, pos = match.FullName+"() closure", node.Pos()
+			spawned = node.Args[match.CallbackArgIdx:]
+			if sel, ok := ast.Unparen(node.Fun).(*ast.SelectorExpr); ok {
+				handles = []ast.Expr{sel.X} // The group or pool is joined by its Wait()
+			}
+
+		default:
+			return
+		}
+
+		name := c.boundReference(pass, spawned, bound)
+		if name == "" || awaited(pass, h.body, n, handles) {
+			return
+		}
+
+		line := pass.Fset.Position(pos).Line
+		if ignoreMap.ShouldIgnore(line, checkerName) {
+			return
+		}
+
+		internal.Report(pass, checkerName, pos,
+			"%s captures request-scoped %q but is not awaited before the handler returns; detach it with context.WithoutCancel",
+			subject, name)
+	})
+}
+
+// requestVars returns the handler parameters carrying the request context:
+// *http.Request, context.Context and configured carrier parameters.
+func (c *Checker) requestVars(pass *analysis.Pass, h handler) map[*types.Var]bool {
+	bound := make(map[*types.Var]bool)
+
+	if h.fnType.Params == nil {
+		return bound
+	}
+
+	for _, field := range h.fnType.Params.List {
+		for _, name := range field.Names {
+			v, ok := pass.TypesInfo.Defs[name].(*types.Var)
+			if !ok || name.Name == "_" {
+				continue
+			}
+			if c.isRequestType(v.Type()) {
+				bound[v] = true
+			}
+		}
+	}
+
+	return bound
+}
+
+// isRequestType reports whether values of type t carry the request context.
+func (c *Checker) isRequestType(t types.Type) bool {
+	return isHTTPRequest(t) || typeutil.IsContextType(t) || carrier.IsCarrierType(t, c.carriers)
+}
+
+// trackDerived adds variables assigned from request-bound values, such as
+// ctx := r.Context() or ctx, cancel := context.WithTimeout(ctx, d), in source order.
+// Values passed through a detach function are detached and not tracked.
+func (c *Checker) trackDerived(pass *analysis.Pass, body *ast.BlockStmt, bound map[*types.Var]bool) {
+	inspectBody(body, func(n ast.Node) {
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			for _, rhs := range node.Rhs {
+				if !c.referencesBound(pass, rhs, bound) {
+					continue
+				}
+				for _, lhs := range node.Lhs {
+					c.bindIdent(pass, lhs, bound)
+				}
+			}
+
+		case *ast.ValueSpec:
+			for _, value := range node.Values {
+				if !c.referencesBound(pass, value, bound) {
+					continue
+				}
+				for _, name := range node.Names {
+					c.bindIdent(pass, name, bound)
+				}
+			}
+		}
+	})
+}
+
+// bindIdent marks a request-typed variable as request-bound.
+func (c *Checker) bindIdent(pass *analysis.Pass, expr ast.Expr, bound map[*types.Var]bool) {
+	ident, ok := expr.(*ast.Ident)
+	if !ok {
+		return
+	}
+
+	v, ok := pass.TypesInfo.ObjectOf(ident).(*types.Var)
+	if ok && c.isRequestType(v.Type()) {
+		bound[v] = true
+	}
+}
+
+// boundReference returns the name of the first request-bound variable referenced
+// by the spawned expressions, or empty string if none is.
+func (c *Checker) boundReference(pass *analysis.Pass, exprs []ast.Expr, bound map[*types.Var]bool) string {
+	for _, expr := range exprs {
+		if name := c.boundName(pass, expr, bound); name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// referencesBound reports whether the expression uses a request-bound variable
+// other than through a detaching call.
+func (c *Checker) referencesBound(pass *analysis.Pass, expr ast.Expr, bound map[*types.Var]bool) bool {
+	return c.boundName(pass, expr, bound) != ""
+}
+
+// boundName returns the name of the first request-bound variable used in node,
+// skipping arguments of detaching calls such as context.WithoutCancel and
+// *http.Request field accesses other than Context().
+func (c *Checker) boundName(pass *analysis.Pass, node ast.Node, bound map[*types.Var]bool) string {
+	var name string
+
+	ast.Inspect(node, func(n ast.Node) bool {
+		if name != "" {
+			return false
+		}
+
+		switch node := n.(type) {
+		case *ast.CallExpr:
+			if fn := funcspec.ExtractFunc(pass, node); fn != nil && probe.IsDetachFunc(fn, c.detachers) {
+				return false
+			}
+
+		case *ast.SelectorExpr:
+			// Reading request fields such as r.URL does not involve its context
+			if ident, ok := node.X.(*ast.Ident); ok && node.Sel.Name != "Context" {
+				if v, ok := pass.TypesInfo.Uses[ident].(*types.Var); ok && isHTTPRequest(v.Type()) {
+					return false
+				}
+			}
+
+		case *ast.Ident:
+			if v, ok := pass.TypesInfo.Uses[node].(*types.Var); ok && bound[v] {
+				name = node.Name
+			}
+		}
+		return true
+	})
+
+	return name
+}
+
+// awaited reports whether the handler joins the spawned work before returning
+// through one of its handles: a Wait() call on it, a receive from it or a range
+// over it after the spawn, or a deferred Wait() on it anywhere in the handler.
+func awaited(pass *analysis.Pass, body *ast.BlockStmt, spawn ast.Node, handles []ast.Expr) bool {
+	if len(handles) == 0 {
+		return false
+	}
+
+	isHandle := func(expr ast.Expr) bool {
+		return slices.ContainsFunc(handles, func(h ast.Expr) bool {
+			return sameHandle(pass, h, expr)
+		})
+	}
+
+	found := false
+
+	inspectBody(body, func(n ast.Node) {
+		if found {
+			return
+		}
+
+		if deferStmt, ok := n.(*ast.DeferStmt); ok {
+			found = isWaitCall(deferStmt.Call, isHandle)
+			return
+		}
+
+		if n.Pos() < spawn.End() {
+			return
+		}
+
+		switch node := n.(type) {
+		case *ast.CallExpr:
+			found = isWaitCall(node, isHandle)
+		case *ast.UnaryExpr:
+			found = node.Op == token.ARROW && isHandle(node.X)
+		case *ast.RangeStmt:
+			found = isHandle(node.X)
+		}
+	})
+
+	return found
+}
+
+// isWaitCall reports whether the call is a Wait() method call on a handle
+// (sync.WaitGroup, errgroup.Group, conc pools, ...).
+func isWaitCall(call *ast.CallExpr, isHandle func(ast.Expr) bool) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	return ok && sel.Sel.Name == "Wait" && isHandle(sel.X)
+}
+
+// signaledHandles returns the handles a goroutine signals on completion:
+// sync.WaitGroup values it calls Done() on, and channels it sends on or closes.
+func signaledHandles(pass *analysis.Pass, lit *ast.FuncLit) []ast.Expr {
+	var handles []ast.Expr
+
+	ast.Inspect(lit.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.SendStmt:
+			handles = append(handles, node.Chan)
+
+		case *ast.CallExpr:
+			if sel, ok := node.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "Done" && isWaitGroup(pass.TypesInfo.TypeOf(sel.X)) {
+				handles = append(handles, sel.X)
+			}
+			if ident, ok := node.Fun.(*ast.Ident); ok && ident.Name == "close" && len(node.Args) == 1 {
+				if _, ok := pass.TypesInfo.Uses[ident].(*types.Builtin); ok {
+					handles = append(handles, node.Args[0])
+				}
+			}
+		}
+		return true
+	})
+
+	return handles
+}
+
+// handleArgs returns the arguments of a spawned function call that can join it:
+// sync.WaitGroup pointers and channels.
+func handleArgs(pass *analysis.Pass, args []ast.Expr) []ast.Expr {
+	var handles []ast.Expr
+
+	for _, arg := range args {
+		typ := pass.TypesInfo.TypeOf(arg)
+		if typ == nil {
+			continue
+		}
+		if _, ok := typ.Underlying().(*types.Chan); ok || isWaitGroup(typ) {
+			handles = append(handles, arg)
+		}
+	}
+
+	return handles
+}
+
+// isWaitGroup reports whether t is sync.WaitGroup or a pointer to it.
+func isWaitGroup(t types.Type) bool {
+	return t != nil && isNamed(typeutil.UnwrapPointer(t), "sync", "WaitGroup")
+}
+
+// sameHandle reports whether two expressions denote the same variable,
+// ignoring address-of operators (e.g., &wg and wg).
+func sameHandle(pass *analysis.Pass, a, b ast.Expr) bool {
+	a, b = unaddr(a), unaddr(b)
+
+	if identA, ok := a.(*ast.Ident); ok {
+		identB, ok := b.(*ast.Ident)
+		if !ok {
+			return false
+		}
+		obj := pass.TypesInfo.ObjectOf(identA)
+		return obj != nil && obj == pass.TypesInfo.ObjectOf(identB)
+	}
+
+	return types.ExprString(a) == types.ExprString(b)
+}
+
+// unaddr strips parentheses and address-of operators from an expression.
+func unaddr(expr ast.Expr) ast.Expr {
+	expr = ast.Unparen(expr)
+	if unary, ok := expr.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+		return unaddr(unary.X)
+	}
+	return expr
+}
+
+// inspectBody visits the nodes of a handler body without descending
+// into nested func literals, which run at another time.
+func inspectBody(body *ast.BlockStmt, visit func(ast.Node)) {
+	ast.Inspect(body, func(n ast.Node) bool {
+		if n == nil {
+			return false
+		}
+		visit(n)
+		_, isLit := n.(*ast.FuncLit)
+		return !isLit
+	})
+}
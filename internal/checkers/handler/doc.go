@@ -0,0 +1,45 @@
+// Package handler detects goroutines that outlive the request handler whose context they capture.
+//
+// # Overview
+//
+// A request context is cancelled when its handler returns. A goroutine that
+// captures it and is not awaited is cancelled at an arbitrary point:
+//
+//	func serve(w http.ResponseWriter, r *http.Request) {
+//	    go func() {
+//	        audit(r.Context())  // Warning: cancelled when serve returns
+//	    }()
+//	}
+//
+// # Handlers
+//
+// The following functions are handlers:
+//
+//   - Functions and func literals with the func(http.ResponseWriter, *http.Request)
+//     signature, including ServeHTTP methods
+//   - gRPC unary methods on types embedding a generated Unimplemented*Server
+//   - Functions matching a -handler-funcs spec, and func literals passed to them
+//
+// # Request Context
+//
+// The *http.Request, context.Context and carrier parameters of a handler are
+// request-bound, as are variables assigned from them in source order
+// (ctx := r.Context(), ctx, cancel := context.WithTimeout(ctx, d), ...).
+// Arguments of context.WithoutCancel and -detach-funcs functions are detached
+// and never request-bound.
+//
+// # Awaiting
+//
+// A spawn is awaited when the handler body, after the spawn, calls Wait(),
+// receives from a channel, selects, or ranges over a channel. A deferred
+// Wait() anywhere in the handler also counts. Nested func literals are not
+// searched, since they run at another time.
+//
+// # Integration
+//
+// Like spawnerlabel, the checker operates at the pass level rather than per node,
+// since handlers need not have a context parameter:
+//
+//	handlerChecker := handler.New(handlers, reg, carriers, detachers)
+//	handlerChecker.Check(pass, ignoreMaps, skipFiles)
+package handler
@@ -0,0 +1,144 @@
+package checkers
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/mpyw/goroutinectx/internal"
+	"github.com/mpyw/goroutinectx/internal/deriver"
+	"github.com/mpyw/goroutinectx/internal/directive/ignore"
+	"github.com/mpyw/goroutinectx/internal/funcspec"
+	"github.com/mpyw/goroutinectx/internal/probe"
+)
+
+// IteratorChecker checks range-over-func loops over concurrent iterators.
+// The loop body of such an iterator runs on another goroutine, so it is
+// treated like a spawned callback.
+type IteratorChecker struct {
+	specs       []funcspec.Spec
+	derivers    *deriver.Matcher
+	requireUsed bool // Report deriver calls whose derived context is discarded
+}
+
+// NewIteratorChecker creates an iterator checker for the given iterator functions.
+// If requireUsed is true, the derived context must also be used.
+func NewIteratorChecker(specs []funcspec.Spec, derivers *deriver.Matcher, requireUsed bool) *IteratorChecker {
+	return &IteratorChecker{
+		specs:       specs,
+		derivers:    derivers,
+		requireUsed: requireUsed,
+	}
+}
+
+// Name returns the checker name for ignore directive matching.
+func (*IteratorChecker) Name() ignore.CheckerName {
+	return ignore.Iterator
+}
+
+// MatchRange returns true if the range statement iterates over a concurrent iterator.
+func (c *IteratorChecker) MatchRange(pass *analysis.Pass, stmt *ast.RangeStmt) bool {
+	return c.iteratorFunc(pass, stmt.X) != nil
+}
+
+// CheckRangeStmt checks that the loop body propagates context.
+func (c *IteratorChecker) CheckRangeStmt(cctx *probe.Context, stmt *ast.RangeStmt) *internal.Result {
+	if len(cctx.CtxNames) == 0 || stmt.Body == nil {
+		return internal.OK()
+	}
+
+	hasDerivers := c.derivers != nil && !c.derivers.IsEmpty()
+
+	fn := c.iteratorFunc(cctx.Pass, stmt.X)
+	name := fn.Name()
+	if spec := c.matchingSpec(fn); spec != nil {
+		name = spec.FullName()
+	}
+
+	if cctx.SSAProg != nil {
+		if misuse := checkDerivedUse(cctx, name+"() loop body", cctx.SSAProg.FindRangeFunc(stmt), c.derivers, c.requireUsed); misuse != nil {
+			return misuse
+		}
+	}
+
+	// Try SSA-based check first, falling back to AST-based check
+	if ok, checked := c.checkBodySSA(cctx, stmt); checked {
+		if ok {
+			return internal.OK()
+		}
+	} else if cctx.BlockUsesContext(stmt.Body) || hasDerivers && c.derivers.SatisfiesAnyGroup(cctx.Pass, stmt.Body) {
+		return internal.OK()
+	}
+
+	if hasDerivers {
+		return internal.Fail(fmt.Sprintf("%s() loop body should use context %q or call goroutine deriver", name, ctxNameOf(cctx)))
+	}
+	return internal.Fail(fmt.Sprintf("%s() loop body should use context %q", name, ctxNameOf(cctx)))
+}
+
+// checkBodySSA checks the loop body using SSA analysis of its synthetic yield function.
+// Returns (result, true) if SSA succeeded, or (false, false) if SSA failed.
+func (c *IteratorChecker) checkBodySSA(cctx *probe.Context, stmt *ast.RangeStmt) (bool, bool) {
+	if cctx.SSAProg == nil || cctx.Tracer == nil {
+		return false, false
+	}
+
+	ssaFn := cctx.SSAProg.FindRangeFunc(stmt)
+	if ssaFn == nil {
+		return false, false
+	}
+
+	if cctx.ClosureCapturesContext(ssaFn) || cctx.Tracer.ClosureUsesDetachedContext(ssaFn, cctx.Detachers) {
+		return true, true
+	}
+
+	if c.derivers != nil && !c.derivers.IsEmpty() && cctx.Tracer.ClosureCallsDeriver(ssaFn, c.derivers).FoundAtStart {
+		return true, true
+	}
+
+	return false, true
+}
+
+// iteratorFunc returns the registered iterator function producing the range expression.
+// Supports direct calls (range parallel.Each(items)) and variables assigned from them.
+func (c *IteratorChecker) iteratorFunc(pass *analysis.Pass, x ast.Expr) *types.Func {
+	x = ast.Unparen(x)
+
+	typ := pass.TypesInfo.TypeOf(x)
+	if typ == nil {
+		return nil
+	}
+	if _, ok := typ.Underlying().(*types.Signature); !ok {
+		return nil // Not range-over-func
+	}
+
+	call, ok := x.(*ast.CallExpr)
+	if !ok {
+		ident, ok := x.(*ast.Ident)
+		if !ok {
+			return nil
+		}
+		call = (&probe.Context{Pass: pass}).CallExprAssignedToIdent(ident)
+		if call == nil {
+			return nil
+		}
+	}
+
+	fn := funcspec.ExtractFunc(pass, call)
+	if fn == nil || c.matchingSpec(fn) == nil {
+		return nil
+	}
+	return fn
+}
+
+// matchingSpec returns the spec matching fn, or nil.
+func (c *IteratorChecker) matchingSpec(fn *types.Func) *funcspec.Spec {
+	for i := range c.specs {
+		if c.specs[i].Matches(fn) {
+			return &c.specs[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package checkers
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	gossa "golang.org/x/tools/go/ssa"
+
+	"github.com/mpyw/goroutinectx/internal"
+	"github.com/mpyw/goroutinectx/internal/directive/ignore"
+	"github.com/mpyw/goroutinectx/internal/funcspec"
+	"github.com/mpyw/goroutinectx/internal/probe"
+	"github.com/mpyw/goroutinectx/internal/registry"
+	"github.com/mpyw/goroutinectx/internal/ssa"
+)
+
+// LoopCancel checks goroutines spawned in loops that use a context derived per
+// iteration, whose cancel is deferred until the enclosing function returns.
+// It implements both GoStmtChecker (go statements) and CallChecker (registered
+// spawn APIs such as errgroup.Group.Go).
+type LoopCancel struct {
+	registry *registry.Registry
+}
+
+// NewLoopCancel creates a loop cancel checker for go statements and the spawn APIs in reg.
+func NewLoopCancel(reg *registry.Registry) *LoopCancel {
+	return &LoopCancel{registry: reg}
+}
+
+// Name returns the checker name for ignore directive matching.
+func (*LoopCancel) Name() ignore.CheckerName {
+	return ignore.LoopCancel
+}
+
+// CheckGoStmt checks the values a go statement passes to its goroutine.
+func (*LoopCancel) CheckGoStmt(cctx *probe.Context, stmt *ast.GoStmt) *internal.Result {
+	if cctx.SSAProg == nil || cctx.Tracer == nil {
+		return internal.OK()
+	}
+
+	g := cctx.SSAProg.FindGoStmt(stmt)
+	if g == nil {
+		return internal.OK()
+	}
+
+	var values []gossa.Value
+	var names []string
+
+	if mc, ok := g.Call.Value.(*gossa.MakeClosure); ok {
+		values, names = closureBindings(mc)
+	}
+
+	// Receiver (if any) and arguments of go f(args)
+	offset := len(g.Call.Args) - len(stmt.Call.Args)
+	for i, arg := range g.Call.Args {
+		values = append(values, arg)
+		if i >= offset {
+			names = append(names, types.ExprString(stmt.Call.Args[i-offset]))
+		} else {
+			names = append(names, arg.Name())
+		}
+	}
+
+	return loopCancelResult(cctx, "goroutine", g, values, names)
+}
+
+// MatchCall returns true if this checker should handle the call.
+func (c *LoopCancel) MatchCall(pass *analysis.Pass, call *ast.CallExpr) bool {
+	fn := funcspec.ExtractFunc(pass, call)
+	if fn == nil {
+		return false
+	}
+	match := c.registry.MatchFunc(fn)
+	return match != nil && !match.AlwaysSpawns
+}
+
+// CheckCall checks the func literal callbacks of a spawn API call.
+func (c *LoopCancel) CheckCall(cctx *probe.Context, call *ast.CallExpr) *internal.Result {
+	if cctx.SSAProg == nil || cctx.Tracer == nil {
+		return internal.OK()
+	}
+
+	fn := funcspec.ExtractFunc(cctx.Pass, call)
+	if fn == nil {
+		return internal.OK()
+	}
+	match := c.registry.MatchFunc(fn)
+	if match == nil || match.CallbackArgIdx >= len(call.Args) {
+		return internal.OK()
+	}
+
+	for _, arg := range call.Args[match.CallbackArgIdx:] {
+		lit, ok := arg.(*ast.FuncLit)
+		if !ok {
+			continue
+		}
+		mc := ssa.MakeClosureOf(cctx.SSAProg.FindFuncLit(lit))
+		if mc == nil {
+			continue
+		}
+		values, names := closureBindings(mc)
+		if result := loopCancelResult(cctx, match.FullName+"() closure", mc, values, names); !result.OK {
+			return result
+		}
+	}
+
+	return internal.OK()
+}
+
+// closureBindings returns the values bound to a closure and the names of its free variables.
+func closureBindings(mc *gossa.MakeClosure) ([]gossa.Value, []string) {
+	closure, ok := mc.Fn.(*gossa.Function)
+	if !ok {
+		return nil, nil
+	}
+
+	names := make([]string, len(mc.Bindings))
+	for i := range mc.Bindings {
+		if i < len(closure.FreeVars) {
+			names[i] = closure.FreeVars[i].Name()
+		}
+	}
+
+	return mc.Bindings, names
+}
+
+// loopCancelResult reports the first spawned value derived in the loop of the spawn
+// instruction whose cancel is only deferred.
+func loopCancelResult(cctx *probe.Context, subject string, spawn gossa.Instruction, values []gossa.Value, names []string) *internal.Result {
+	idx, deriver := cctx.Tracer.DeferredLoopCancel(spawn, values)
+	if idx < 0 {
+		return internal.OK()
+	}
+
+	return internal.Fail(fmt.Sprintf(
+		"%s uses %q derived by %s() in a loop, but its cancel is deferred until the function returns; call cancel when the goroutine or iteration is done",
+		subject, names[idx], deriver.FullName(),
+	))
+}
@@ -18,7 +18,8 @@ import (
 type SpawnCallbackChecker struct {
 	checkerName ignore.CheckerName
 	entries     []SpawnCallbackEntry
-	derivers    *deriver.Matcher
+	derivers    *deriver.Rules
+	requireUsed bool // Report deriver calls whose derived context is discarded
 }
 
 // SpawnCallbackEntry defines a function that spawns its callback argument as a goroutine.
@@ -28,11 +29,14 @@ type SpawnCallbackEntry struct {
 }
 
 // NewSpawnCallbackChecker creates a new SpawnCallbackChecker.
-func NewSpawnCallbackChecker(name ignore.CheckerName, entries []SpawnCallbackEntry, derivers *deriver.Matcher) *SpawnCallbackChecker {
+// The deriver rule is selected per call, so each spawn API may require a different deriver.
+// If requireUsed is true, the derived context must also be used.
+func NewSpawnCallbackChecker(name ignore.CheckerName, entries []SpawnCallbackEntry, derivers *deriver.Rules, requireUsed bool) *SpawnCallbackChecker {
 	return &SpawnCallbackChecker{
 		checkerName: name,
 		entries:     entries,
 		derivers:    derivers,
+		requireUsed: requireUsed,
 	}
 }
 
@@ -67,59 +71,67 @@ func (c *SpawnCallbackChecker) CheckCall(cctx *probe.Context, call *ast.CallExpr
 		if !entry.Spec.Matches(fn) {
 			continue
 		}
-		return c.checkSingleArg(cctx, call, entry)
+		return c.checkSingleArg(cctx, call, entry, c.derivers.ForCall(c.checkerName, fn))
 	}
 
 	return internal.OK()
 }
 
-func (c *SpawnCallbackChecker) checkSingleArg(cctx *probe.Context, call *ast.CallExpr, entry SpawnCallbackEntry) *internal.Result {
+func (c *SpawnCallbackChecker) checkSingleArg(cctx *probe.Context, call *ast.CallExpr, entry SpawnCallbackEntry, derivers *deriver.Matcher) *internal.Result {
 	if entry.CallbackArgIdx >= len(call.Args) {
 		return internal.OK()
 	}
 
+	ctxName := ctxNameOf(cctx)
+
 	arg := call.Args[entry.CallbackArgIdx]
-	if c.checkArg(cctx, arg) {
-		return internal.OK()
+
+	// A callback that receives its own context must not ignore it in favor of the outer one
+	if lit, ok := arg.(*ast.FuncLit); ok {
+		if captured, ignores := cctx.FuncLitIgnoresContextParam(lit); ignores {
+			return internal.Fail(fmt.Sprintf("%s() closure should use its own context parameter instead of capturing %q", entry.Spec.FullName(), captured))
+		}
+		if misuse := checkDerivedUseOfLit(cctx, entry.Spec.FullName()+"() closure", lit, derivers, c.requireUsed); misuse != nil {
+			return misuse
+		}
 	}
 
-	ctxName := "ctx"
-	if len(cctx.CtxNames) > 0 {
-		ctxName = cctx.CtxNames[0]
+	if c.checkArg(cctx, arg, derivers) {
+		return internal.OK()
 	}
 
 	// Format error message based on whether deriver is configured
-	if c.derivers != nil && !c.derivers.IsEmpty() {
+	if derivers != nil && !derivers.IsEmpty() {
 		return internal.Fail(fmt.Sprintf("%s() closure should use context %q or call goroutine deriver", entry.Spec.FullName(), ctxName))
 	}
 	return internal.Fail(fmt.Sprintf("%s() closure should use context %q", entry.Spec.FullName(), ctxName))
 }
 
-func (c *SpawnCallbackChecker) checkArg(cctx *probe.Context, arg ast.Expr) bool {
+func (c *SpawnCallbackChecker) checkArg(cctx *probe.Context, arg ast.Expr, derivers *deriver.Matcher) bool {
 	if len(cctx.CtxNames) == 0 {
 		return true
 	}
 
 	// Try SSA-based check first
 	if lit, ok := arg.(*ast.FuncLit); ok {
-		if result, ok := c.checkFuncLitSSA(cctx, lit); ok {
+		if result, ok := c.checkFuncLitSSA(cctx, lit, derivers); ok {
 			return result
 		}
 	}
 
 	// Fall back to AST-based check
-	return c.checkArgFromAST(cctx, arg)
+	return c.checkArgFromAST(cctx, arg, derivers)
 }
 
 // checkFuncLitSSA checks a func literal using SSA analysis.
 // Returns (result, true) if SSA succeeded, or (false, false) if SSA failed.
-func (c *SpawnCallbackChecker) checkFuncLitSSA(cctx *probe.Context, lit *ast.FuncLit) (bool, bool) {
+func (c *SpawnCallbackChecker) checkFuncLitSSA(cctx *probe.Context, lit *ast.FuncLit, derivers *deriver.Matcher) (bool, bool) {
 	if cctx.SSAProg == nil || cctx.Tracer == nil {
 		return false, false
 	}
 
-	// If func lit has context param, it's OK
-	if cctx.FuncLitHasContextParam(lit) {
+	// If func lit has context param or detaches intentionally, it's OK
+	if cctx.FuncLitHasContextParam(lit) || cctx.FuncLitUsesDetachedContext(lit) {
 		return true, true
 	}
 
@@ -129,13 +141,13 @@ func (c *SpawnCallbackChecker) checkFuncLitSSA(cctx *probe.Context, lit *ast.Fun
 	}
 
 	// Check if closure captures context
-	if cctx.Tracer.ClosureCapturesContext(ssaFn, cctx.Carriers) {
+	if cctx.ClosureCapturesContext(ssaFn) {
 		return true, true
 	}
 
 	// If derivers configured, also check if deriver is called
-	if c.derivers != nil && !c.derivers.IsEmpty() {
-		result := cctx.Tracer.ClosureCallsDeriver(ssaFn, c.derivers)
+	if derivers != nil && !derivers.IsEmpty() {
+		result := cctx.Tracer.ClosureCallsDeriver(ssaFn, derivers)
 		if result.FoundAtStart {
 			return true, true
 		}
@@ -144,9 +156,9 @@ func (c *SpawnCallbackChecker) checkFuncLitSSA(cctx *probe.Context, lit *ast.Fun
 	return false, true
 }
 
-func (c *SpawnCallbackChecker) checkArgFromAST(cctx *probe.Context, arg ast.Expr) bool {
+func (c *SpawnCallbackChecker) checkArgFromAST(cctx *probe.Context, arg ast.Expr, derivers *deriver.Matcher) bool {
 	if lit, ok := arg.(*ast.FuncLit); ok {
-		return c.checkFuncLitAST(cctx, lit)
+		return c.checkFuncLitAST(cctx, lit, derivers)
 	}
 
 	if ident, ok := arg.(*ast.Ident); ok {
@@ -154,7 +166,7 @@ func (c *SpawnCallbackChecker) checkArgFromAST(cctx *probe.Context, arg ast.Expr
 		if len(assigns) == 0 {
 			return true
 		}
-		return c.checkFuncLitAssignments(cctx, assigns)
+		return c.checkFuncLitAssignments(cctx, assigns, derivers)
 	}
 
 	if call, ok := arg.(*ast.CallExpr); ok {
@@ -174,7 +186,7 @@ func (c *SpawnCallbackChecker) checkArgFromAST(cctx *probe.Context, arg ast.Expr
 
 // checkFuncLitAssignments checks all func literal assignments from last unconditional onwards.
 // ALL must pass for the check to succeed.
-func (c *SpawnCallbackChecker) checkFuncLitAssignments(cctx *probe.Context, assigns []probe.FuncLitAssignment) bool {
+func (c *SpawnCallbackChecker) checkFuncLitAssignments(cctx *probe.Context, assigns []probe.FuncLitAssignment, derivers *deriver.Matcher) bool {
 	// Find the index of the last unconditional assignment
 	lastUnconditionalIdx := -1
 	for i := len(assigns) - 1; i >= 0; i-- {
@@ -193,7 +205,7 @@ func (c *SpawnCallbackChecker) checkFuncLitAssignments(cctx *probe.Context, assi
 	// Check all assignments from startIdx onwards
 	// ALL must pass (because conditional assignments may override)
 	for i := startIdx; i < len(assigns); i++ {
-		if !c.checkFuncLitAST(cctx, assigns[i].Lit) {
+		if !c.checkFuncLitAST(cctx, assigns[i].Lit, derivers) {
 			return false
 		}
 	}
@@ -201,15 +213,15 @@ func (c *SpawnCallbackChecker) checkFuncLitAssignments(cctx *probe.Context, assi
 }
 
 // checkFuncLitAST checks a func literal using AST-based analysis.
-func (c *SpawnCallbackChecker) checkFuncLitAST(cctx *probe.Context, lit *ast.FuncLit) bool {
-	// Check context capture
-	if cctx.FuncLitCapturesContext(lit) {
+func (c *SpawnCallbackChecker) checkFuncLitAST(cctx *probe.Context, lit *ast.FuncLit, derivers *deriver.Matcher) bool {
+	// Check context capture or intentional detach
+	if cctx.FuncLitCapturesContext(lit) || cctx.FuncLitUsesDetachedContext(lit) {
 		return true
 	}
 
 	// If derivers configured, also check if deriver is called
-	if c.derivers != nil && !c.derivers.IsEmpty() {
-		if c.derivers.SatisfiesAnyGroup(cctx.Pass, lit.Body) {
+	if derivers != nil && !derivers.IsEmpty() {
+		if derivers.SatisfiesAnyGroup(cctx.Pass, lit.Body) {
 			return true
 		}
 	}
@@ -222,23 +234,23 @@ func (c *SpawnCallbackChecker) checkFuncLitAST(cctx *probe.Context, lit *ast.Fun
 // =============================================================================
 
 // NewErrgroupChecker creates the errgroup checker.
-func NewErrgroupChecker(derivers *deriver.Matcher) *SpawnCallbackChecker {
+func NewErrgroupChecker(derivers *deriver.Rules, requireUsed bool) *SpawnCallbackChecker {
 	return NewSpawnCallbackChecker(ignore.Errgroup, []SpawnCallbackEntry{
 		{Spec: funcspec.Spec{PkgPath: "golang.org/x/sync/errgroup", TypeName: "Group", FuncName: "Go"}, CallbackArgIdx: 0},
 		{Spec: funcspec.Spec{PkgPath: "golang.org/x/sync/errgroup", TypeName: "Group", FuncName: "TryGo"}, CallbackArgIdx: 0},
-	}, derivers)
+	}, derivers, requireUsed)
 }
 
 // NewWaitgroupChecker creates the waitgroup checker (Go 1.25+).
-func NewWaitgroupChecker(derivers *deriver.Matcher) *SpawnCallbackChecker {
+func NewWaitgroupChecker(derivers *deriver.Rules, requireUsed bool) *SpawnCallbackChecker {
 	return NewSpawnCallbackChecker(ignore.Waitgroup, []SpawnCallbackEntry{
 		{Spec: funcspec.Spec{PkgPath: "sync", TypeName: "WaitGroup", FuncName: "Go"}, CallbackArgIdx: 0},
-	}, derivers)
+	}, derivers, requireUsed)
 }
 
 // NewConcChecker creates the conc checker.
-func NewConcChecker(derivers *deriver.Matcher) *SpawnCallbackChecker {
-	return NewSpawnCallbackChecker(ignore.Errgroup, []SpawnCallbackEntry{
+func NewConcChecker(derivers *deriver.Rules, requireUsed bool) *SpawnCallbackChecker {
+	return NewSpawnCallbackChecker(ignore.Conc, []SpawnCallbackEntry{
 		// conc.Pool.Go
 		{Spec: funcspec.Spec{PkgPath: "github.com/sourcegraph/conc", TypeName: "Pool", FuncName: "Go"}, CallbackArgIdx: 0},
 		// conc.WaitGroup.Go
@@ -273,7 +285,7 @@ func NewConcChecker(derivers *deriver.Matcher) *SpawnCallbackChecker {
 		{Spec: funcspec.Spec{PkgPath: "github.com/sourcegraph/conc/iter", TypeName: "Mapper", FuncName: "Map"}, CallbackArgIdx: 1},
 		// iter.Mapper.MapErr
 		{Spec: funcspec.Spec{PkgPath: "github.com/sourcegraph/conc/iter", TypeName: "Mapper", FuncName: "MapErr"}, CallbackArgIdx: 1},
-	}, derivers)
+	}, derivers, requireUsed)
 }
 
 // =============================================================================
@@ -282,8 +294,9 @@ func NewConcChecker(derivers *deriver.Matcher) *SpawnCallbackChecker {
 
 // SpawnerChecker checks calls to spawner-marked functions.
 type SpawnerChecker struct {
-	spawners SpawnerMap
-	derivers *deriver.Matcher
+	spawners    SpawnerMap
+	derivers    *deriver.Matcher
+	requireUsed bool // Report deriver calls whose derived context is discarded
 }
 
 // SpawnerMap interface for checking if a function is a spawner.
@@ -292,10 +305,12 @@ type SpawnerMap interface {
 }
 
 // NewSpawnerChecker creates a spawner checker.
-func NewSpawnerChecker(spawners SpawnerMap, derivers *deriver.Matcher) *SpawnerChecker {
+// If requireUsed is true, the derived context must also be used.
+func NewSpawnerChecker(spawners SpawnerMap, derivers *deriver.Matcher, requireUsed bool) *SpawnerChecker {
 	return &SpawnerChecker{
-		spawners: spawners,
-		derivers: derivers,
+		spawners:    spawners,
+		derivers:    derivers,
+		requireUsed: requireUsed,
 	}
 }
 
@@ -329,10 +344,7 @@ func (c *SpawnerChecker) CheckCall(cctx *probe.Context, call *ast.CallExpr) *int
 		return internal.OK()
 	}
 
-	ctxName := "ctx"
-	if len(cctx.CtxNames) > 0 {
-		ctxName = cctx.CtxNames[0]
-	}
+	ctxName := ctxNameOf(cctx)
 
 	// Format error message based on whether deriver is configured
 	msgFormat := "%s() func argument should use context %q"
@@ -342,6 +354,16 @@ func (c *SpawnerChecker) CheckCall(cctx *probe.Context, call *ast.CallExpr) *int
 
 	// Report each failing argument at its position
 	for _, arg := range funcArgs {
+		if lit, ok := arg.(*ast.FuncLit); ok {
+			if captured, ignores := cctx.FuncLitIgnoresContextParam(lit); ignores {
+				cctx.Pass.Reportf(arg.Pos(), "%s() func argument should use its own context parameter instead of capturing %q", fn.Name(), captured)
+				continue
+			}
+			if misuse := checkDerivedUseOfLit(cctx, fn.Name()+"() func argument", lit, c.derivers, c.requireUsed); misuse != nil {
+				cctx.Pass.Reportf(misuse.Pos, "%s", misuse.Message)
+				continue
+			}
+		}
 		if !c.checkFuncArg(cctx, arg) {
 			cctx.Pass.Reportf(arg.Pos(), msgFormat, fn.Name(), ctxName)
 		}
@@ -409,7 +431,7 @@ func (c *SpawnerChecker) checkFuncLitSSA(cctx *probe.Context, lit *ast.FuncLit)
 		return false, false
 	}
 
-	if cctx.FuncLitHasContextParam(lit) {
+	if cctx.FuncLitHasContextParam(lit) || cctx.FuncLitUsesDetachedContext(lit) {
 		return true, true
 	}
 
@@ -418,7 +440,7 @@ func (c *SpawnerChecker) checkFuncLitSSA(cctx *probe.Context, lit *ast.FuncLit)
 		return false, false
 	}
 
-	if cctx.Tracer.ClosureCapturesContext(ssaFn, cctx.Carriers) {
+	if cctx.ClosureCapturesContext(ssaFn) {
 		return true, true
 	}
 
@@ -434,7 +456,7 @@ func (c *SpawnerChecker) checkFuncLitSSA(cctx *probe.Context, lit *ast.FuncLit)
 
 // checkFuncLitAST checks a func literal using AST analysis for SpawnerChecker.
 func (c *SpawnerChecker) checkFuncLitAST(cctx *probe.Context, lit *ast.FuncLit) bool {
-	if cctx.FuncLitCapturesContext(lit) {
+	if cctx.FuncLitCapturesContext(lit) || cctx.FuncLitUsesDetachedContext(lit) {
 		return true
 	}
 
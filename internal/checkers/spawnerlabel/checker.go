@@ -7,6 +7,7 @@ import (
 	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/ssa"
 
+	"github.com/mpyw/goroutinectx/internal"
 	"github.com/mpyw/goroutinectx/internal/directive/ignore"
 	"github.com/mpyw/goroutinectx/internal/directive/spawner"
 	"github.com/mpyw/goroutinectx/internal/registry"
@@ -96,7 +97,8 @@ func (c *Checker) checkFunction(pass *analysis.Pass, fnDecl *ast.FuncDecl, ignor
 	if !isMarked && spawnInfo != nil {
 		line := pass.Fset.Position(fnDecl.Pos()).Line
 		if !ignoreMap.ShouldIgnore(line, checkerName) {
-			pass.Reportf(
+			internal.Report(
+				pass, checkerName,
 				fnDecl.Name.Pos(),
 				"function %q should have //goroutinectx:spawner directive (calls %s with func argument)",
 				fnDecl.Name.Name,
@@ -109,7 +111,8 @@ func (c *Checker) checkFunction(pass *analysis.Pass, fnDecl *ast.FuncDecl, ignor
 	if isMarked && spawnInfo == nil && !hasFuncParams(fn) {
 		line := pass.Fset.Position(fnDecl.Pos()).Line
 		if !ignoreMap.ShouldIgnore(line, checkerName) {
-			pass.Reportf(
+			internal.Report(
+				pass, checkerName,
 				fnDecl.Name.Pos(),
 				"function %q has unnecessary //goroutinectx:spawner directive",
 				fnDecl.Name.Name,
@@ -0,0 +1,324 @@
+package checkers
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/mpyw/goroutinectx/internal"
+	"github.com/mpyw/goroutinectx/internal/directive/ignore"
+	"github.com/mpyw/goroutinectx/internal/funcspec"
+	"github.com/mpyw/goroutinectx/internal/probe"
+)
+
+// stdlibRule describes how a standard library callback relates to the context in scope.
+type stdlibRule int
+
+const (
+	// stdlibUse callbacks run later on another goroutine and should use the context.
+	stdlibUse stdlibRule = iota
+
+	// stdlibNoCapture callbacks run once the context is likely done,
+	// so capturing it only retains it.
+	stdlibNoCapture
+
+	// stdlibDoneContext callbacks run once the context they are registered on
+	// (the first argument) is done, so they must not pass it or its derivatives
+	// downstream, nor capture it at all, which only retains it; a context
+	// detached before registering is required instead.
+	stdlibDoneContext
+
+	// stdlibSync callbacks run synchronously on the calling goroutine
+	// and are never reported.
+	stdlibSync
+)
+
+// stdlibAPI is a standard library function taking a callback.
+type stdlibAPI struct {
+	spec   funcspec.Spec
+	name   string // Display name (methods promoted from unexported types use the exported one)
+	argIdx int    // Index of the callback argument
+	rule   stdlibRule
+	reason string // Why capturing is wrong, for stdlibNoCapture
+}
+
+var stdlibAPIs = []stdlibAPI{
+	{
+		spec:   funcspec.Spec{PkgPath: "time", FuncName: "AfterFunc"},
+		name:   "time.AfterFunc",
+		argIdx: 1,
+		rule:   stdlibUse,
+	},
+	{
+		spec:   funcspec.Spec{PkgPath: "net/http", TypeName: "Server", FuncName: "RegisterOnShutdown"},
+		name:   "http.Server.RegisterOnShutdown",
+		argIdx: 0,
+		rule:   stdlibUse,
+	},
+	{
+		spec:   funcspec.Spec{PkgPath: "context", FuncName: "AfterFunc"},
+		name:   "context.AfterFunc",
+		argIdx: 1,
+		rule:   stdlibDoneContext,
+	},
+	{
+		spec:   funcspec.Spec{PkgPath: "runtime", FuncName: "SetFinalizer"},
+		name:   "runtime.SetFinalizer",
+		argIdx: 1,
+		rule:   stdlibNoCapture,
+		reason: "which it retains until the object is collected",
+	},
+	{
+		spec:   funcspec.Spec{PkgPath: "testing", TypeName: "common", FuncName: "Cleanup"},
+		name:   "testing.T.Cleanup",
+		argIdx: 0,
+		rule:   stdlibNoCapture,
+		reason: "which may be cancelled before cleanup runs (t.Context() is)",
+	},
+	{
+		spec:   funcspec.Spec{PkgPath: "sync", TypeName: "Once", FuncName: "Do"},
+		name:   "sync.Once.Do",
+		argIdx: 0,
+		rule:   stdlibSync,
+	},
+}
+
+// Stdlib checks callbacks passed to standard library APIs that run them
+// on other goroutines or at a later time.
+type Stdlib struct{}
+
+// Name returns the checker name for ignore directive matching.
+func (*Stdlib) Name() ignore.CheckerName {
+	return ignore.Stdlib
+}
+
+// MatchCall returns true if this checker should handle the call.
+func (*Stdlib) MatchCall(pass *analysis.Pass, call *ast.CallExpr) bool {
+	return matchStdlibAPI(pass, call) != nil
+}
+
+// CheckCall checks the callback of a standard library API call.
+func (*Stdlib) CheckCall(cctx *probe.Context, call *ast.CallExpr) *internal.Result {
+	api := matchStdlibAPI(cctx.Pass, call)
+	if api == nil || api.argIdx >= len(call.Args) {
+		return internal.OK()
+	}
+
+	callback := call.Args[api.argIdx]
+
+	switch api.rule {
+	case stdlibUse:
+		if stdlibCallbackUsesContext(cctx, callback) {
+			return internal.OK()
+		}
+		return internal.Fail(fmt.Sprintf("%s() callback should use context %q", api.name, ctxNameOf(cctx)))
+
+	case stdlibNoCapture:
+		lit, ok := callback.(*ast.FuncLit)
+		if !ok || !stdlibFuncLitCapturesContext(cctx, lit) {
+			return internal.OK()
+		}
+		return internal.Fail(fmt.Sprintf("%s() callback captures context %q, %s", api.name, ctxNameOf(cctx), api.reason))
+
+	case stdlibDoneContext:
+		lit, ok := callback.(*ast.FuncLit)
+		if !ok {
+			return internal.OK()
+		}
+		ident, ok := ast.Unparen(call.Args[0]).(*ast.Ident)
+		if !ok {
+			return internal.OK()
+		}
+		v := cctx.VarOf(ident)
+		if v == nil {
+			return internal.OK()
+		}
+		// context.WithoutCancel is the suggested fix, so it always detaches
+		detached := *cctx
+		detached.Detachers = probe.WithWithoutCancel(cctx.Detachers)
+		cctx := &detached
+		if name, callee := doneContextPassed(cctx, call, lit, v); name != "" {
+			return internal.Fail(fmt.Sprintf(
+				"%s() callback passes %q to %s, but %s is already done when the callback runs; detach it before registering (e.g., context.WithoutCancel(%s)) instead",
+				api.name, name, callee, ident.Name, ident.Name,
+			))
+		}
+		if !funcLitReferences(cctx, lit, v) {
+			return internal.OK()
+		}
+		return internal.Fail(fmt.Sprintf(
+			"%s() callback captures %q, the context it is registered on; it runs once %s is done, so capturing it only retains it",
+			api.name, ident.Name, ident.Name,
+		))
+	}
+
+	return internal.OK()
+}
+
+// matchStdlibAPI returns the standard library API called, or nil if none.
+func matchStdlibAPI(pass *analysis.Pass, call *ast.CallExpr) *stdlibAPI {
+	fn := funcspec.ExtractFunc(pass, call)
+	if fn == nil {
+		return nil
+	}
+
+	for i := range stdlibAPIs {
+		if stdlibAPIs[i].spec.Matches(fn) {
+			return &stdlibAPIs[i]
+		}
+	}
+
+	return nil
+}
+
+// stdlibCallbackUsesContext checks if a callback uses the context in scope.
+// Callbacks that cannot be resolved to func literals are not reported.
+func stdlibCallbackUsesContext(cctx *probe.Context, callback ast.Expr) bool {
+	switch cb := callback.(type) {
+	case *ast.FuncLit:
+		return stdlibFuncLitCapturesContext(cctx, cb) || cctx.FuncLitUsesDetachedContext(cb)
+
+	case *ast.Ident:
+		return cctx.FuncLitsAllCaptureContext(cctx.FuncLitAssignmentsOfIdent(cb))
+
+	case *ast.CallExpr:
+		return cctx.FactoryCallReturnsContextUsingFunc(cb)
+	}
+
+	return true
+}
+
+// stdlibFuncLitCapturesContext checks if a func literal captures context,
+// preferring SSA free variables over the AST fallback.
+func stdlibFuncLitCapturesContext(cctx *probe.Context, lit *ast.FuncLit) bool {
+	if captured, ok := cctx.FuncLitCapturesContextSSA(lit); ok {
+		return captured
+	}
+	return cctx.FuncLitUsesContext(lit)
+}
+
+// funcLitReferences checks if a func literal references the variable.
+func funcLitReferences(cctx *probe.Context, lit *ast.FuncLit, v *types.Var) bool {
+	found := false
+	ast.Inspect(lit.Body, func(n ast.Node) bool {
+		if found {
+			return false
+		}
+		if ident, ok := n.(*ast.Ident); ok && cctx.Pass.TypesInfo.Uses[ident] == v {
+			found = true
+		}
+		return true
+	})
+	return found
+}
+
+// doneContextPassed returns the first done context variable passed to a downstream
+// call in the callback, and the callee expression. Contexts derived from the done
+// context are traced through SSA; without SSA, only the variable itself is checked.
+func doneContextPassed(cctx *probe.Context, register *ast.CallExpr, lit *ast.FuncLit, v *types.Var) (string, string) {
+	if cctx.SSAProg == nil || cctx.Tracer == nil {
+		return passesDoneContext(cctx, lit, map[*types.Var]bool{v: true})
+	}
+
+	ssaCall, ssaFn := cctx.SSAProg.FindCall(register), cctx.SSAProg.FindFuncLit(lit)
+	if ssaCall == nil || ssaFn == nil {
+		return passesDoneContext(cctx, lit, map[*types.Var]bool{v: true})
+	}
+
+	pass := cctx.Tracer.FirstDonePass(ssaCall, ssaFn, cctx.Detachers)
+	if pass == nil {
+		return "", ""
+	}
+
+	callee := "a call"
+	ast.Inspect(lit.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.CallExpr:
+			if node.Lparen == pass.Pos {
+				callee = types.ExprString(node.Fun)
+				return false
+			}
+		case *ast.GoStmt:
+			if node.Call.Lparen == pass.Pos || node.Go == pass.Pos {
+				callee = types.ExprString(node.Call.Fun)
+				return false
+			}
+		case *ast.DeferStmt:
+			if node.Call.Lparen == pass.Pos || node.Defer == pass.Pos {
+				callee = types.ExprString(node.Call.Fun)
+				return false
+			}
+		}
+		return true
+	})
+
+	name := pass.Name
+	if name == "" {
+		name = v.Name()
+	}
+	return name, callee
+}
+
+// passesDoneContext returns the first done context variable passed to a downstream
+// call in the func literal, and the callee expression. Method calls on the done
+// context itself (e.g., ctx.Err()) and detach function calls are allowed.
+func passesDoneContext(cctx *probe.Context, lit *ast.FuncLit, done map[*types.Var]bool) (string, string) {
+	var name, callee string
+
+	ast.Inspect(lit.Body, func(n ast.Node) bool {
+		if name != "" {
+			return false
+		}
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		if cctx.IsDetachCall(call) {
+			return false
+		}
+		for _, arg := range call.Args {
+			if ident := passedDone(cctx, arg, done); ident != nil {
+				name, callee = ident.Name, types.ExprString(call.Fun)
+				return false
+			}
+		}
+		return true
+	})
+
+	return name, callee
+}
+
+// passedDone returns the done context identifier an argument passes along,
+// skipping selections on it (ctx.Err(), ctx.Value(k)) and detach function calls.
+func passedDone(cctx *probe.Context, arg ast.Expr, done map[*types.Var]bool) *ast.Ident {
+	var found *ast.Ident
+
+	ast.Inspect(arg, func(n ast.Node) bool {
+		if found != nil {
+			return false
+		}
+		switch node := n.(type) {
+		case *ast.SelectorExpr:
+			if ident, ok := node.X.(*ast.Ident); ok {
+				if v := cctx.VarOf(ident); v != nil && done[v] {
+					return false
+				}
+			}
+		case *ast.CallExpr:
+			if cctx.IsDetachCall(node) {
+				return false
+			}
+		case *ast.FuncLit:
+			return false
+		case *ast.Ident:
+			if v := cctx.VarOf(node); v != nil && done[v] {
+				found = node
+			}
+		}
+		return true
+	})
+
+	return found
+}
@@ -0,0 +1,96 @@
+// Package config defines the settings shared by the goroutinectx analyzers
+// and builds their configuration from a pass.
+package config
+
+import (
+	"flag"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/mpyw/goroutinectx/internal/deriver"
+	"github.com/mpyw/goroutinectx/internal/directive/carrier"
+	"github.com/mpyw/goroutinectx/internal/directive/spawner"
+	"github.com/mpyw/goroutinectx/internal/facts"
+	"github.com/mpyw/goroutinectx/internal/funcspec"
+)
+
+// Config holds the flags deciding how context flows into spawned functions.
+// Both the main analyzer and the graph analyzer register them, so a spawn
+// site is described the same way as it is checked.
+type Config struct {
+	GoroutineDeriver      string
+	GoroutineDeriverRules string
+	GoroutineDeriverOnce  bool
+	ExternalSpawner       string
+	ConcurrentIterators   string
+	ContextCarriers       string
+	ContextCarriersAuto   bool
+	ContextFields         bool
+	DetachFuncs           string
+}
+
+// RegisterFlags registers the flags of the config on fs.
+func (c *Config) RegisterFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.GoroutineDeriver, "goroutine-deriver", "",
+		"require goroutines to call this function to derive context (e.g., pkg.Func or pkg.Type.Method)")
+	fs.StringVar(&c.GoroutineDeriverRules, "goroutine-deriver-rules", "",
+		"semicolon-separated deriver rules keyed by checker or spawn API, overriding -goroutine-deriver (e.g., goroutine=pkg.Func;pkg.Type.Method=pkg.Other;waitgroup=)")
+	fs.BoolVar(&c.GoroutineDeriverOnce, "goroutine-deriver-once", false,
+		"accept nested spawns capturing a context already derived by an enclosing spawned closure, instead of requiring the deriver at every level")
+	fs.StringVar(&c.ExternalSpawner, "external-spawner", "",
+		"comma-separated list of external spawner functions (e.g., pkg.Func or pkg.Type.Method)")
+	fs.StringVar(&c.ConcurrentIterators, "concurrent-iterator", "",
+		"comma-separated list of iterator functions whose range-over-func loop body runs on another goroutine (e.g., pkg.Func or pkg.Type.Method)")
+	fs.StringVar(&c.ContextCarriers, "context-carriers", "",
+		"comma-separated list of types to treat as context carriers (e.g., github.com/labstack/echo/v4.Context)")
+	fs.BoolVar(&c.ContextCarriersAuto, "context-carriers-auto", false,
+		"also treat types implementing context.Context (e.g., structs embedding it) as context carriers")
+	fs.BoolVar(&c.ContextFields, "context-fields", false,
+		"treat context.Context struct fields as context: methods whose receiver has one are checked, and goroutines reading one from a captured struct propagate it")
+	fs.StringVar(&c.DetachFuncs, "detach-funcs", "",
+		"comma-separated list of functions whose result is an intentionally detached context; closures using it are accepted (e.g., context.WithoutCancel or pkg.Detach)")
+}
+
+// Derivers builds the deriver rules from -goroutine-deriver, -goroutine-deriver-rules
+// and -goroutine-deriver-once, adding the //goroutinectx:deriver directives and
+// deriver wrappers found by the facts analyzer.
+func (c *Config) Derivers(result *facts.Result) (*deriver.Rules, error) {
+	derivers, err := deriver.NewRules(c.GoroutineDeriver, c.GoroutineDeriverRules)
+	if err != nil {
+		return nil, err
+	}
+
+	derivers.AddDerivers(result.Derivers)
+	derivers.SetInherited(c.GoroutineDeriverOnce)
+	derivers.SetWrappers(result.Wrappers)
+
+	return derivers, nil
+}
+
+// Carriers builds the context carriers from -context-carriers and
+// -context-carriers-auto, adding the //goroutinectx:carrier directives
+// found by the facts analyzer, resolved against the package of the pass.
+func (c *Config) Carriers(pass *analysis.Pass, result *facts.Result) []carrier.Carrier {
+	carriers := append(carrier.Parse(c.ContextCarriers), result.Carriers...)
+	if c.ContextCarriersAuto {
+		carriers = append(carriers, carrier.Context)
+	}
+
+	return carrier.Resolve(pass.Pkg, carriers)
+}
+
+// Spawners builds the spawner map from //goroutinectx:spawner directives
+// and -external-spawner.
+func (c *Config) Spawners(pass *analysis.Pass) *spawner.Map {
+	return spawner.Build(pass, c.ExternalSpawner)
+}
+
+// Iterators parses -concurrent-iterator.
+func (c *Config) Iterators() []funcspec.Spec {
+	return funcspec.ParseList(c.ConcurrentIterators)
+}
+
+// Detachers parses -detach-funcs.
+func (c *Config) Detachers() []funcspec.Spec {
+	return funcspec.ParseList(c.DetachFuncs)
+}
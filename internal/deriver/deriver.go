@@ -17,6 +17,45 @@ import (
 type Matcher struct {
 	OrGroups [][]funcspec.Spec
 	Original string
+
+	// Inherited accepts a nested spawn whose captured context was already
+	// derived in an enclosing spawned closure, instead of requiring the
+	// deriver to be called again at every level.
+	Inherited bool
+
+	// Wrappers maps functions that return a context derived from their context
+	// parameter to the functions they call to derive it (see [Fact]). Calling a
+	// wrapper satisfies every spec matched by one of these functions.
+	Wrappers map[*types.Func][]funcspec.Spec
+}
+
+// Fact marks a function that returns a context derived from its context
+// parameter, on every path, recording the functions called to derive it.
+// Calls to wrappers it makes are expanded, so the list is transitive.
+type Fact struct {
+	Derives []funcspec.Spec
+}
+
+// AFact implements analysis.Fact.
+func (*Fact) AFact() {}
+
+func (f *Fact) String() string {
+	names := make([]string, len(f.Derives))
+	for i, spec := range f.Derives {
+		names[i] = spec.String()
+	}
+	return "derives(" + strings.Join(names, ", ") + ")"
+}
+
+// DirectiveFact marks a function declared as a deriver with the
+// //goroutinectx:deriver directive.
+type DirectiveFact struct{}
+
+// AFact implements analysis.Fact.
+func (*DirectiveFact) AFact() {}
+
+func (*DirectiveFact) String() string {
+	return "deriver"
 }
 
 // NewMatcher creates a Matcher from a derive function string.
@@ -59,7 +98,7 @@ func (m *Matcher) SatisfiesAnyGroup(pass *analysis.Pass, node ast.Node) bool {
 	calledFuncs := collectCalledFuncs(pass, node)
 
 	for _, andGroup := range m.OrGroups {
-		if groupSatisfied(calledFuncs, andGroup) {
+		if m.groupSatisfied(calledFuncs, andGroup) {
 			return true
 		}
 	}
@@ -76,7 +115,7 @@ func (m *Matcher) IsEmpty() bool {
 func (m *Matcher) MatchesFunc(fn *types.Func) bool {
 	for _, andGroup := range m.OrGroups {
 		for _, spec := range andGroup {
-			if spec.Matches(fn) {
+			if m.Satisfies(spec, fn) {
 				return true
 			}
 		}
@@ -84,6 +123,20 @@ func (m *Matcher) MatchesFunc(fn *types.Func) bool {
 	return false
 }
 
+// Satisfies checks if calling fn satisfies the spec, either by matching it
+// or by wrapping a function that matches it.
+func (m *Matcher) Satisfies(spec funcspec.Spec, fn *types.Func) bool {
+	if spec.Matches(fn) {
+		return true
+	}
+	for _, derived := range m.Wrappers[fn.Origin()] {
+		if spec.MatchesSpec(derived) {
+			return true
+		}
+	}
+	return false
+}
+
 // collectCalledFuncs collects all types.Func that are called within the node.
 // Does NOT traverse into nested function literals.
 func collectCalledFuncs(pass *analysis.Pass, node ast.Node) []*types.Func {
@@ -110,9 +163,9 @@ func collectCalledFuncs(pass *analysis.Pass, node ast.Node) []*types.Func {
 }
 
 // groupSatisfied checks if ALL specs in the AND group are satisfied.
-func groupSatisfied(calledFuncs []*types.Func, andGroup []funcspec.Spec) bool {
+func (m *Matcher) groupSatisfied(calledFuncs []*types.Func, andGroup []funcspec.Spec) bool {
 	for _, spec := range andGroup {
-		if !specSatisfied(calledFuncs, spec) {
+		if !m.specSatisfied(calledFuncs, spec) {
 			return false
 		}
 	}
@@ -120,9 +173,9 @@ func groupSatisfied(calledFuncs []*types.Func, andGroup []funcspec.Spec) bool {
 }
 
 // specSatisfied checks if the spec is satisfied by any of the called functions.
-func specSatisfied(calledFuncs []*types.Func, spec funcspec.Spec) bool {
+func (m *Matcher) specSatisfied(calledFuncs []*types.Func, spec funcspec.Spec) bool {
 	for _, fn := range calledFuncs {
-		if spec.Matches(fn) {
+		if m.Satisfies(spec, fn) {
 			return true
 		}
 	}
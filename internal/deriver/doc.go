@@ -86,4 +86,44 @@
 //	if matcher == nil || matcher.IsEmpty() {
 //	    return internal.OK()  // No derive check required
 //	}
+//
+// # Wrappers
+//
+// [Matcher.Wrappers] maps functions that always derive the context they return
+// to the functions they call to derive it, from the [Fact]s inferred by the
+// facts analyzer. [Matcher.Satisfies] accepts a call to such a wrapper for any
+// spec matched by one of those functions:
+//
+//	// tracing.StartSpan calls apm.NewGoroutineContext on its ctx parameter
+//	matcher.Satisfies(apmSpec, startSpanFunc) // true
+//
+// Use [Rules.SetWrappers] to set them on every matcher.
+//
+// # Directive Derivers
+//
+// Functions marked with //goroutinectx:deriver get a [DirectiveFact]. The facts
+// analyzer collects those of the package and of its direct imports. Use
+// [Rules.AddDerivers] to add each of them as an OR group of its own to the
+// default matcher (created if no deriver is configured) and to every
+// non-empty rule.
+//
+// # Rules
+//
+// The -goroutine-deriver-rules flag overrides the default matcher per
+// checker or per spawn API. Use [NewRules] to combine both flags:
+//
+//	rules, err := deriver.NewRules(
+//	    "apm.NewGoroutineContext",
+//	    "golang.org/x/sync/errgroup.Group.Go=apm.NewSegmentContext;waitgroup=",
+//	)
+//
+// Entries without "=", unknown checker names and malformed spawn APIs
+// are rejected with an error wrapping [ErrInvalidRule].
+//
+//	rules.ForChecker(ignore.Goroutine)     // apm.NewGoroutineContext (default)
+//	rules.ForChecker(ignore.Waitgroup)     // empty matcher (no deriver required)
+//	rules.ForCall(ignore.Errgroup, goFunc) // apm.NewSegmentContext
+//
+// [Rules.ForCall] prefers a spawn API rule, then a checker rule, then the default.
+// Both methods return nil when no deriver is configured.
 package deriver
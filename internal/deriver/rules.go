@@ -0,0 +1,185 @@
+package deriver
+
+import (
+	"errors"
+	"fmt"
+	"go/types"
+	"slices"
+	"strings"
+
+	"github.com/mpyw/goroutinectx/internal/directive/ignore"
+	"github.com/mpyw/goroutinectx/internal/funcspec"
+)
+
+// Rules selects a deriver Matcher per checker or per spawn API.
+// Spawn API rules take precedence over checker rules, which take
+// precedence over the default matcher.
+type Rules struct {
+	Default  *Matcher
+	checkers map[ignore.CheckerName]*Matcher
+	apis     []apiRule
+}
+
+// apiRule binds a deriver matcher to a single spawn API.
+type apiRule struct {
+	spec    funcspec.Spec
+	matcher *Matcher
+}
+
+// ErrInvalidRule is returned by [NewRules] for a malformed -goroutine-deriver-rules entry.
+var ErrInvalidRule = errors.New("invalid -goroutine-deriver-rules entry")
+
+// NewRules creates Rules from the default deriver string and a
+// semicolon-separated list of "key=derivers" rules.
+// A key without a dot is a checker name (e.g., "goroutine", "errgroup");
+// otherwise it is a spawn API (e.g., "golang.org/x/sync/errgroup.Group.Go").
+// An empty right-hand side means no deriver is required for that key.
+// Returns an error wrapping [ErrInvalidRule] for entries without "=",
+// unknown checker names and malformed spawn APIs, so typos are not
+// silently ignored.
+func NewRules(defaultStr, rulesStr string) (*Rules, error) {
+	r := &Rules{
+		checkers: make(map[ignore.CheckerName]*Matcher),
+	}
+
+	if defaultStr != "" {
+		r.Default = NewMatcher(defaultStr)
+	}
+
+	for rule := range strings.SplitSeq(rulesStr, ";") {
+		if strings.TrimSpace(rule) == "" {
+			continue
+		}
+
+		key, value, ok := strings.Cut(rule, "=")
+		if !ok {
+			return nil, fmt.Errorf("%w %q: want key=derivers", ErrInvalidRule, rule)
+		}
+
+		key = strings.TrimSpace(key)
+		matcher := NewMatcher(strings.TrimSpace(value))
+
+		switch {
+		case key == "":
+			return nil, fmt.Errorf("%w %q: missing checker name or spawn API", ErrInvalidRule, rule)
+
+		case strings.Contains(key, "."):
+			spec := funcspec.Parse(key)
+			if spec.PkgPath == "" || spec.FuncName == "" {
+				return nil, fmt.Errorf("%w %q: malformed spawn API %q", ErrInvalidRule, rule, key)
+			}
+			r.apis = append(r.apis, apiRule{spec: spec, matcher: matcher})
+
+		case !ignore.IsKnown(ignore.CheckerName(key)):
+			if suggestion, ok := ignore.Suggest(ignore.CheckerName(key)); ok {
+				return nil, fmt.Errorf("%w %q: unknown checker %q (did you mean %q?)", ErrInvalidRule, rule, key, suggestion)
+			}
+			return nil, fmt.Errorf("%w %q: unknown checker %q", ErrInvalidRule, rule, key)
+
+		default:
+			r.checkers[ignore.CheckerName(key)] = matcher
+		}
+	}
+
+	return r, nil
+}
+
+// AddDerivers adds each function as an OR group of its own to the default
+// matcher, creating it if no deriver is configured, and to every non-empty
+// rule. Rules explicitly requiring no deriver are left as they are.
+func (r *Rules) AddDerivers(specs []funcspec.Spec) {
+	if len(specs) == 0 {
+		return
+	}
+
+	if r.Default == nil {
+		r.Default = &Matcher{}
+	}
+	r.Default.addDerivers(specs)
+
+	for _, m := range r.checkers {
+		if !m.IsEmpty() {
+			m.addDerivers(specs)
+		}
+	}
+	for _, rule := range r.apis {
+		if !rule.matcher.IsEmpty() {
+			rule.matcher.addDerivers(specs)
+		}
+	}
+}
+
+// addDerivers appends each function as an OR group and to the original string.
+func (m *Matcher) addDerivers(specs []funcspec.Spec) {
+	for _, spec := range specs {
+		if slices.ContainsFunc(m.OrGroups, func(group []funcspec.Spec) bool {
+			return len(group) == 1 && group[0] == spec
+		}) {
+			continue
+		}
+		m.OrGroups = append(m.OrGroups, []funcspec.Spec{spec})
+		if m.Original != "" {
+			m.Original += ","
+		}
+		m.Original += spec.String()
+	}
+}
+
+// SetInherited sets [Matcher.Inherited] on every configured matcher.
+func (r *Rules) SetInherited(inherited bool) {
+	if r.Default != nil {
+		r.Default.Inherited = inherited
+	}
+	for _, m := range r.checkers {
+		m.Inherited = inherited
+	}
+	for _, rule := range r.apis {
+		rule.matcher.Inherited = inherited
+	}
+}
+
+// SetWrappers sets [Matcher.Wrappers] on every configured matcher.
+func (r *Rules) SetWrappers(wrappers map[*types.Func][]funcspec.Spec) {
+	if r.Default != nil {
+		r.Default.Wrappers = wrappers
+	}
+	for _, m := range r.checkers {
+		m.Wrappers = wrappers
+	}
+	for _, rule := range r.apis {
+		rule.matcher.Wrappers = wrappers
+	}
+}
+
+// ForChecker returns the matcher for the checker, falling back to the default.
+// Returns nil if no deriver is configured for the checker.
+func (r *Rules) ForChecker(name ignore.CheckerName) *Matcher {
+	if r == nil {
+		return nil
+	}
+
+	if m, ok := r.checkers[name]; ok {
+		return m
+	}
+
+	return r.Default
+}
+
+// ForCall returns the matcher for the called spawn API, falling back to
+// the checker rule and then the default.
+// Returns nil if no deriver is configured for the call.
+func (r *Rules) ForCall(name ignore.CheckerName, fn *types.Func) *Matcher {
+	if r == nil {
+		return nil
+	}
+
+	if fn != nil {
+		for _, rule := range r.apis {
+			if rule.spec.Matches(fn) {
+				return rule.matcher
+			}
+		}
+	}
+
+	return r.ForChecker(name)
+}
@@ -0,0 +1,81 @@
+package deriver
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mpyw/goroutinectx/internal/directive/ignore"
+)
+
+func TestNewRules(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		rules   string
+		wantErr bool
+	}{
+		{
+			name:  "empty",
+			rules: "",
+		},
+		{
+			name:  "checker and spawn API rules",
+			rules: "goroutine=pkg.F;golang.org/x/sync/errgroup.Group.Go=pkg.G;waitgroup=",
+		},
+		{
+			name:  "trailing separator",
+			rules: "goroutine=pkg.F;",
+		},
+		{
+			name:    "missing equals sign",
+			rules:   "goroutine",
+			wantErr: true,
+		},
+		{
+			name:    "missing key",
+			rules:   "=pkg.F",
+			wantErr: true,
+		},
+		{
+			name:    "unknown checker",
+			rules:   "gorutine=pkg.F",
+			wantErr: true,
+		},
+		{
+			name:    "malformed spawn API",
+			rules:   ".Go=pkg.F",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewRules("pkg.Default", tt.rules)
+			if tt.wantErr != errors.Is(err, ErrInvalidRule) {
+				t.Errorf("NewRules(%q) error = %v, wantErr %v", tt.rules, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewRulesSelection(t *testing.T) {
+	t.Parallel()
+
+	rules, err := NewRules("pkg.Default", "waitgroup=;errgroup=pkg.Errgroup")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if m := rules.ForChecker(ignore.Goroutine); m == nil || m.Original != "pkg.Default" {
+		t.Errorf("ForChecker(goroutine) = %v, want pkg.Default", m)
+	}
+	if m := rules.ForChecker(ignore.Waitgroup); m == nil || !m.IsEmpty() {
+		t.Errorf("ForChecker(waitgroup) = %v, want empty matcher", m)
+	}
+	if m := rules.ForChecker(ignore.Errgroup); m == nil || m.Original != "pkg.Errgroup" {
+		t.Errorf("ForChecker(errgroup) = %v, want pkg.Errgroup", m)
+	}
+}
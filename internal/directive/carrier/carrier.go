@@ -13,12 +13,25 @@ import (
 type Carrier struct {
 	PkgPath  string
 	TypeName string
+	Accessor string // Method returning the carried context, set by //goroutinectx:carrier only
+
+	// Interface is the carrier type if it is a non-empty interface, set by [Resolve].
+	// Types implementing it (or whose pointer does) match structurally.
+	Interface *types.Interface
 }
 
+// Context is the carrier matching types that implement context.Context themselves
+// (e.g., structs embedding it), once resolved.
+var Context = Carrier{PkgPath: "context", TypeName: "Context"}
+
 // Matches checks if the given type matches this carrier.
 func (c Carrier) Matches(t types.Type) bool {
 	t = typeutil.UnwrapPointer(t)
 
+	if c.Interface != nil && t != nil && (types.Implements(t, c.Interface) || types.Implements(types.NewPointer(t), c.Interface)) {
+		return true
+	}
+
 	named, ok := t.(*types.Named)
 	if !ok {
 		return false
@@ -32,6 +45,62 @@ func (c Carrier) Matches(t types.Type) bool {
 	return matchPkg(obj.Pkg().Path(), c.PkgPath) && obj.Name() == c.TypeName
 }
 
+// Resolve looks up the carriers among pkg and its transitive imports, setting
+// [Carrier.Interface] for carriers declared as non-generic, non-empty interfaces.
+// Carriers whose package is not reachable from pkg are returned unchanged.
+func Resolve(pkg *types.Package, carriers []Carrier) []Carrier {
+	if pkg == nil || len(carriers) == 0 {
+		return carriers
+	}
+
+	var pkgs []*types.Package
+	visited := make(map[*types.Package]bool)
+	for work := []*types.Package{pkg}; len(work) > 0; {
+		p := work[len(work)-1]
+		work = work[:len(work)-1]
+		if visited[p] {
+			continue
+		}
+		visited[p] = true
+		pkgs = append(pkgs, p)
+		work = append(work, p.Imports()...)
+	}
+
+	resolved := make([]Carrier, len(carriers))
+	for i, c := range carriers {
+		resolved[i] = c
+		for _, p := range pkgs {
+			if !matchPkg(p.Path(), c.PkgPath) {
+				continue
+			}
+			if iface := interfaceOf(p.Scope().Lookup(c.TypeName)); iface != nil {
+				resolved[i].Interface = iface
+				break
+			}
+		}
+	}
+
+	return resolved
+}
+
+// interfaceOf returns the interface declared by a type name, or nil if the object
+// is not a type name, is generic, or declares an empty interface (which every type implements).
+func interfaceOf(obj types.Object) *types.Interface {
+	tn, ok := obj.(*types.TypeName)
+	if !ok {
+		return nil
+	}
+	if named, ok := tn.Type().(*types.Named); ok && named.TypeParams().Len() > 0 {
+		return nil
+	}
+
+	iface, ok := tn.Type().Underlying().(*types.Interface)
+	if !ok || iface.NumMethods() == 0 {
+		return nil
+	}
+	return iface
+}
+
 // matchPkg checks if pkgPath matches targetPkg, allowing version suffixes.
 func matchPkg(pkgPath, targetPkg string) bool {
 	if pkgPath == targetPkg {
@@ -56,6 +125,23 @@ func IsCarrierType(t types.Type, carriers []Carrier) bool {
 	return false
 }
 
+// AccessorOf returns the accessor a use of a carrier type must call to propagate the
+// carried context. Returns "" if any use propagates it, i.e., if the type matches no
+// carrier or a carrier without an accessor.
+func AccessorOf(t types.Type, carriers []Carrier) string {
+	accessor := ""
+	for _, c := range carriers {
+		if !c.Matches(t) {
+			continue
+		}
+		if c.Accessor == "" {
+			return ""
+		}
+		accessor = c.Accessor
+	}
+	return accessor
+}
+
 // Parse parses a comma-separated list of context carriers.
 func Parse(s string) []Carrier {
 	if s == "" {
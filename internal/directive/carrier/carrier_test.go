@@ -131,3 +131,53 @@ func TestParse(t *testing.T) {
 		})
 	}
 }
+
+func TestParseCarrierComment(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		text         string
+		wantAccessor string
+		wantOK       bool
+	}{
+		{
+			name:   "directive without accessor",
+			text:   "//goroutinectx:carrier",
+			wantOK: true,
+		},
+		{
+			name:         "directive with accessor",
+			text:         "//goroutinectx:carrier Context",
+			wantAccessor: "Context",
+			wantOK:       true,
+		},
+		{
+			name:         "directive with accessor and trailing comment",
+			text:         "// goroutinectx:carrier Context // request context",
+			wantAccessor: "Context",
+			wantOK:       true,
+		},
+		{
+			name:   "other directive",
+			text:   "//goroutinectx:carriers",
+			wantOK: false,
+		},
+		{
+			name:   "plain comment",
+			text:   "// carrier of the request context",
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			accessor, ok := parseCarrierComment(tt.text)
+			if accessor != tt.wantAccessor || ok != tt.wantOK {
+				t.Errorf("parseCarrierComment(%q) = (%q, %v), want (%q, %v)", tt.text, accessor, ok, tt.wantAccessor, tt.wantOK)
+			}
+		})
+	}
+}
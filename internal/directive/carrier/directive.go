@@ -0,0 +1,145 @@
+package carrier
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/mpyw/goroutinectx/internal/typeutil"
+)
+
+// Directive is a type declaration marked with //goroutinectx:carrier.
+type Directive struct {
+	Type     *types.TypeName
+	Accessor string    // Method returning the carried context.Context, if named
+	Pos      token.Pos // Position of the directive comment
+}
+
+// Fact marks a type declared as a context carrier with the
+// //goroutinectx:carrier directive.
+type Fact struct {
+	Accessor string
+}
+
+// AFact implements analysis.Fact.
+func (*Fact) AFact() {}
+
+func (f *Fact) String() string {
+	if f.Accessor != "" {
+		return "carrier(" + f.Accessor + ")"
+	}
+	return "carrier"
+}
+
+// Carrier returns the carrier declared by the directive.
+func (d Directive) Carrier() Carrier {
+	return Carrier{
+		PkgPath:  d.Type.Pkg().Path(),
+		TypeName: d.Type.Name(),
+		Accessor: d.Accessor,
+	}
+}
+
+// ValidAccessor checks if the accessor is omitted, or names a method of the
+// type (or a pointer to it) taking no arguments and returning a context.Context.
+func (d Directive) ValidAccessor() bool {
+	if d.Accessor == "" {
+		return true
+	}
+
+	var mset *types.MethodSet
+	if types.IsInterface(d.Type.Type()) {
+		mset = types.NewMethodSet(d.Type.Type())
+	} else {
+		mset = types.NewMethodSet(types.NewPointer(d.Type.Type()))
+	}
+	sel := mset.Lookup(d.Type.Pkg(), d.Accessor)
+	if sel == nil {
+		return false
+	}
+
+	sig, ok := sel.Type().(*types.Signature)
+	return ok && sig.Params().Len() == 0 && sig.Results().Len() == 1 &&
+		typeutil.IsContextType(sig.Results().At(0).Type())
+}
+
+// Build scans files for type declarations marked with the directive.
+func Build(pass *analysis.Pass) []Directive {
+	var directives []Directive
+
+	for _, file := range pass.Files {
+		directives = append(directives, buildForFile(pass, file)...)
+	}
+
+	return directives
+}
+
+// buildForFile scans a single file for carrier directives.
+func buildForFile(pass *analysis.Pass, file *ast.File) []Directive {
+	lineComments := make(map[int]*ast.Comment)
+
+	for _, cg := range file.Comments {
+		for _, c := range cg.List {
+			if _, ok := parseCarrierComment(c.Text); ok {
+				line := pass.Fset.Position(c.Pos()).Line
+				lineComments[line] = c
+			}
+		}
+	}
+
+	if len(lineComments) == 0 {
+		return nil
+	}
+
+	var directives []Directive
+
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.TYPE {
+			continue
+		}
+
+		for _, spec := range genDecl.Specs {
+			typeSpec, ok := spec.(*ast.TypeSpec)
+			if !ok {
+				continue
+			}
+
+			line := pass.Fset.Position(typeSpec.Pos()).Line
+			c, hasDirective := lineComments[line-1]
+			if !hasDirective {
+				continue
+			}
+
+			obj, ok := pass.TypesInfo.Defs[typeSpec.Name].(*types.TypeName)
+			if !ok {
+				continue
+			}
+
+			accessor, _ := parseCarrierComment(c.Text)
+			directives = append(directives, Directive{Type: obj, Accessor: accessor, Pos: c.Pos()})
+		}
+	}
+
+	return directives
+}
+
+// parseCarrierComment checks if a comment is a carrier directive,
+// returning the accessor method it names, if any.
+func parseCarrierComment(text string) (string, bool) {
+	text = strings.TrimPrefix(text, "//")
+	text = strings.TrimSpace(text)
+
+	rest, ok := strings.CutPrefix(text, "goroutinectx:carrier")
+	if !ok || (rest != "" && rest[0] != ' ' && rest[0] != '\t') {
+		return "", false
+	}
+
+	if fields := strings.Fields(rest); len(fields) > 0 {
+		return fields[0], true
+	}
+	return "", true
+}
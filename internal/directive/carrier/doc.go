@@ -1,4 +1,5 @@
-// Package carrier provides context carrier type parsing.
+// Package carrier provides context carrier type parsing and the
+// //goroutinectx:carrier directive.
 //
 // # Overview
 //
@@ -24,11 +25,30 @@
 //
 //	-carrier=github.com/labstack/echo/v4.Context,github.com/gin-gonic/gin.Context
 //
+// # Directive
+//
+// Types can declare themselves as carriers, optionally naming the method
+// that returns the carried context:
+//
+//	//goroutinectx:carrier Context
+//	type Request struct { ... }
+//
+// Use [Build] to find the marked types of a package. The facts analyzer
+// exports a [Fact] for each of them, so that importing packages treat them
+// as carriers too. [Directive.ValidAccessor] checks that the accessor is a
+// method without arguments returning context.Context.
+//
+// A carrier with an accessor is only propagated by calling the accessor
+// (r.Context()) or by passing the carrier itself on; other uses, such as
+// r.Param("id"), do not count. Use [AccessorOf] to find the accessor of a type.
+//
 // # Carrier Structure
 //
 //	type Carrier struct {
 //	    PkgPath  string  // Package path
 //	    TypeName string  // Type name
+//	    Accessor string  // Context accessor method (directive only)
+//	    Interface *types.Interface // Set by Resolve for interface carriers
 //	}
 //
 // # Parsing
@@ -53,8 +73,21 @@
 //
 // The matching handles:
 //   - Pointer types: *echo.Context matches echo.Context
+//   - Implementations of resolved interface carriers
 //   - Version suffixes: echo/v4 matches echo/v4, echo/v5, etc.
 //
+// # Interface Carriers
+//
+// Use [Resolve] to look up the carriers among the analyzed package and its
+// imports. A carrier declared as a non-empty interface then matches every type
+// implementing it, or whose pointer does, using types.Implements:
+//
+//	carriers = carrier.Resolve(pass.Pkg, carriers)
+//	// echo.Context carrier also matches custom implementations of echo.Context
+//
+// Appending [Context] before resolving matches types implementing
+// context.Context themselves, such as structs embedding it (-context-carriers-auto).
+//
 // # IsCarrierType Helper
 //
 // Use [IsCarrierType] to check if a type matches any carrier:
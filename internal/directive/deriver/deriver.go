@@ -0,0 +1,70 @@
+// Package deriver handles //goroutinectx:deriver directives.
+package deriver
+
+import (
+	"go/ast"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// Build scans files for functions marked with the directive.
+// Functions are returned in declaration order.
+func Build(pass *analysis.Pass) []*types.Func {
+	var funcs []*types.Func
+
+	for _, file := range pass.Files {
+		funcs = append(funcs, buildForFile(pass, file)...)
+	}
+
+	return funcs
+}
+
+// buildForFile scans a single file for deriver directives.
+func buildForFile(pass *analysis.Pass, file *ast.File) []*types.Func {
+	lineComments := make(map[int]string)
+
+	for _, cg := range file.Comments {
+		for _, c := range cg.List {
+			if isDeriverComment(c.Text) {
+				line := pass.Fset.Position(c.Pos()).Line
+				lineComments[line] = c.Text
+			}
+		}
+	}
+
+	if len(lineComments) == 0 {
+		return nil
+	}
+
+	var funcs []*types.Func
+
+	for _, decl := range file.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+
+		funcLine := pass.Fset.Position(funcDecl.Pos()).Line
+		if _, hasDirective := lineComments[funcLine-1]; !hasDirective {
+			continue
+		}
+
+		fn, ok := pass.TypesInfo.ObjectOf(funcDecl.Name).(*types.Func)
+		if !ok {
+			continue
+		}
+
+		funcs = append(funcs, fn)
+	}
+
+	return funcs
+}
+
+// isDeriverComment checks if a comment is a deriver directive.
+func isDeriverComment(text string) bool {
+	text = strings.TrimPrefix(text, "//")
+	text = strings.TrimSpace(text)
+	return text == "goroutinectx:deriver" || strings.HasPrefix(text, "goroutinectx:deriver ")
+}
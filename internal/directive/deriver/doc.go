@@ -0,0 +1,38 @@
+// Package deriver provides //goroutinectx:deriver directive parsing.
+//
+// # Overview
+//
+// The deriver directive marks a function as deriving a context for a
+// goroutine, like a function listed in -goroutine-deriver. Library authors
+// can ship the declaration with the function instead of having every
+// consumer configure the flag.
+//
+// # Directive Usage
+//
+// Mark a function with the directive in its doc comment:
+//
+//	//goroutinectx:deriver
+//	func NewGoroutineContext(ctx context.Context) context.Context {
+//	    ...
+//	}
+//
+// Goroutines in the package and in packages importing it must then call it:
+//
+//	func handler(ctx context.Context) {
+//	    go func() {
+//	        doWork(ctx)  // Warning: should call apm.NewGoroutineContext
+//	    }()
+//	}
+//
+// # Parsing
+//
+// Use [Build] to find all deriver-marked functions in a package:
+//
+//	funcs := deriver.Build(pass)
+//
+// The facts analyzer exports a DirectiveFact for each of them, so that
+// direct importers see them too. Packages reaching the declaring package only
+// through other dependencies do not. Every deriver found this way is added to the
+// deriver matchers as an OR group of its own (see Rules.AddDerivers in
+// the internal/deriver package).
+package deriver
@@ -6,7 +6,8 @@
 // that control analyzer behavior:
 //
 //	directive/
-//	├── carrier/   # Context carrier type configuration
+//	├── carrier/   # Context carrier types and //goroutinectx:carrier directive
+//	├── deriver/   # //goroutinectx:deriver directive
 //	├── ignore/    # //goroutinectx:ignore directive
 //	└── spawner/   # //goroutinectx:spawner directive
 //
@@ -22,15 +23,32 @@
 //	//goroutinectx:ignore goroutine
 //	//goroutinectx:ignore goroutine,errgroup
 //	//goroutinectx:spawner
+//	//goroutinectx:deriver
+//	//goroutinectx:carrier Context
 //
 // # Carrier Directive
 //
-// Context carrier types are configured via flag, not directive:
+// Context carrier types are configured via flag:
 //
 //	-carrier=github.com/labstack/echo/v4.Context
 //
+// or declared on the type, optionally naming its context accessor:
+//
+//	//goroutinectx:carrier Context
+//	type Request struct { ... }
+//
 // See [carrier] package for details.
 //
+// # Deriver Directive
+//
+// Marks a function as deriving the context of a goroutine, like the
+// -goroutine-deriver flag. It applies to the package and its importers:
+//
+//	//goroutinectx:deriver
+//	func Fork(ctx context.Context) context.Context { ... }
+//
+// See [deriver] package for details.
+//
 // # Ignore Directive
 //
 // Suppresses warnings for the next line or same line:
@@ -14,6 +14,29 @@
 //
 //	go func() { ... }()  //goroutinectx:ignore  // Also works
 //
+// # Regions, Functions and Files
+//
+// Larger scopes use dedicated directives:
+//
+//	//goroutinectx:ignore-begin goroutine - legacy fan-out
+//	go func() { ... }()  // Warning suppressed
+//	go func() { ... }()  // Warning suppressed
+//	//goroutinectx:ignore-end
+//
+//	//goroutinectx:ignore - legacy worker
+//	func worker(ctx context.Context) { ... }  // Whole body suppressed
+//
+//	//goroutinectx:ignore-file errgroup - vendored  // Whole file suppressed
+//
+// An ignore-end closes the most recent ignore-begin; an unterminated
+// ignore-begin covers the rest of the file. [Map.GetUnmatchedRegions] returns
+// unterminated ignore-begin and stray ignore-end directives, which are reported.
+//
+// # Reasons
+//
+// Text after " - " is recorded as the reason. [Map.GetMissingReasons]
+// returns directives without one (used by -require-ignore-reason).
+//
 // # Checker-Specific Ignores
 //
 // Specify checker names to ignore only specific checks:
@@ -33,11 +56,23 @@
 //	│ goroutinederive │ go statement deriver function calls         │
 //	│ errgroup        │ errgroup.Group.Go callback context          │
 //	│ waitgroup       │ sync.WaitGroup.Go callback context          │
+//	│ conc            │ sourcegraph/conc callback context           │
 //	│ spawner         │ //goroutinectx:spawner function calls       │
 //	│ spawnerlabel    │ Spawner label directive validation          │
 //	│ gotask          │ gotask library function calls               │
+//	│ admission       │ Semaphore and limited errgroup admission    │
+//	│ iterator        │ Concurrent range-over-func loop bodies      │
+//	│ handler         │ Request-scoped goroutines in handlers       │
+//	│ ctxfield        │ Context-storing structs crossing goroutines │
+//	│ stdlib          │ Standard library async callbacks            │
+//	│ loopcancel      │ Per-iteration contexts with deferred cancel │
 //	└─────────────────┴─────────────────────────────────────────────┘
 //
+// Names are validated against this registry when directives are parsed.
+// [Map.GetUnknownCheckers] returns unregistered names together with the
+// closest registered name as a "did you mean" suggestion. The errgroup
+// name is an alias that also covers conc.
+//
 // # Parsing
 //
 // Use [BuildIgnoreMaps] to parse ignore directives from all files:
@@ -50,9 +85,11 @@
 //
 // # Map Structure
 //
-//	type Map map[int]CheckerSet  // line number → ignored checkers
-//
-//	type CheckerSet map[CheckerName]bool
+//	type Map struct {
+//	    lines   map[int]*Entry  // line number → directive
+//	    regions []region        // begin/end, function and file ranges
+//	    entries []*Entry        // all directives in source order
+//	}
 //
 // # Checking Ignores
 //
@@ -65,8 +102,8 @@
 //
 // # Unused Ignore Detection
 //
-// The package tracks which ignore directives are used and reports
-// unused ones as warnings:
+// The package tracks which ignore directives actually suppress a diagnostic
+// and reports unused ones as warnings:
 //
 //	//goroutinectx:ignore  // Warning: unused ignore directive
 //	normalCode()           // No warning to suppress
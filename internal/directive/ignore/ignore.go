@@ -2,8 +2,10 @@
 package ignore
 
 import (
+	"cmp"
 	"go/ast"
 	"go/token"
+	"slices"
 	"strings"
 )
 
@@ -16,103 +18,241 @@ const (
 	GoroutineDerive CheckerName = "goroutinederive"
 	Waitgroup       CheckerName = "waitgroup"
 	Errgroup        CheckerName = "errgroup"
+	Conc            CheckerName = "conc"
 	Spawner         CheckerName = "spawner"
 	Spawnerlabel    CheckerName = "spawnerlabel"
 	Gotask          CheckerName = "gotask"
+	Admission       CheckerName = "admission"
+	Iterator        CheckerName = "iterator"
+	Handler         CheckerName = "handler"
+	Ctxfield        CheckerName = "ctxfield"
+	Stdlib          CheckerName = "stdlib"
+	LoopCancel      CheckerName = "loopcancel"
 )
 
 // Entry tracks an ignore directive and its usage.
 type Entry struct {
-	pos      token.Pos            // Position of the ignore comment
-	checkers []CheckerName        // List of checker names (empty = all)
-	used     map[CheckerName]bool // Track usage per checker
+	pos       token.Pos            // Position of the ignore comment
+	directive string               // Directive name (e.g., "goroutinectx:ignore-begin")
+	checkers  []CheckerName        // List of checker names (empty = all)
+	unknown   []CheckerName        // Checker names not in the registry
+	reason    string               // Text after " - " (empty if none)
+	used      map[CheckerName]bool // Track usage per checker
 }
 
-// Map tracks ignore entries by line number.
-type Map map[int]*Entry
+// region is a line range covered by an ignore-begin/end pair,
+// a function-level directive, or a file-level directive.
+type region struct {
+	entry      *Entry
+	start, end int
+}
+
+// Map tracks ignore entries by line number and by region.
+type Map struct {
+	lines     map[int]*Entry
+	regions   []region
+	entries   []*Entry          // All entries in source order
+	unmatched []UnmatchedRegion // Unterminated ignore-begin and stray ignore-end directives
+}
 
 // EnabledCheckers tracks which checkers are currently enabled.
 type EnabledCheckers map[CheckerName]bool
 
+// directiveKind distinguishes the ignore directive variants.
+type directiveKind int
+
+const (
+	kindLine  directiveKind = iota // //goroutinectx:ignore
+	kindBegin                      // //goroutinectx:ignore-begin
+	kindEnd                        // //goroutinectx:ignore-end
+	kindFile                       // //goroutinectx:ignore-file
+)
+
+// directivePrefixes maps directive names to their kinds.
+// Longer names come first so that "ignore" does not shadow its variants.
+var directivePrefixes = []struct {
+	name string
+	kind directiveKind
+}{
+	{"goroutinectx:ignore-begin", kindBegin},
+	{"goroutinectx:ignore-end", kindEnd},
+	{"goroutinectx:ignore-file", kindFile},
+	{"goroutinectx:ignore", kindLine},
+}
+
+// directive is a parsed ignore comment.
+type directive struct {
+	name     string
+	kind     directiveKind
+	checkers []CheckerName
+	reason   string
+}
+
 // Build scans a file for ignore comments and returns a map.
+//
+//   - //goroutinectx:ignore covers its own line and the next line.
+//     In the doc comment of a function declaration, it also covers the whole function.
+//   - //goroutinectx:ignore-begin and //goroutinectx:ignore-end cover the lines between them.
+//     An unterminated ignore-begin covers the rest of the file, and is reported
+//     along with stray ignore-end directives (see [Map.GetUnmatchedRegions]).
+//   - //goroutinectx:ignore-file covers the whole file.
 func Build(fset *token.FileSet, file *ast.File) Map {
-	m := make(Map)
+	m := Map{lines: make(map[int]*Entry)}
+
+	funcDocs := make(map[*ast.CommentGroup]*ast.FuncDecl)
+	for _, decl := range file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Doc != nil {
+			funcDocs[fn.Doc] = fn
+		}
+	}
+
+	fileEnd := fset.Position(file.End()).Line
+	var open []region
 
 	for _, cg := range file.Comments {
 		for _, c := range cg.List {
-			if checkers, ok := parseComment(c.Text); ok {
-				line := fset.Position(c.Pos()).Line
-				m[line] = &Entry{
-					pos:      c.Pos(),
-					checkers: checkers,
-					used:     make(map[CheckerName]bool),
+			d, ok := parseComment(c.Text)
+			if !ok {
+				continue
+			}
+
+			line := fset.Position(c.Pos()).Line
+
+			if d.kind == kindEnd {
+				if n := len(open); n > 0 {
+					r := open[n-1]
+					r.end = line
+					m.regions = append(m.regions, r)
+					open = open[:n-1]
+				} else {
+					m.unmatched = append(m.unmatched, UnmatchedRegion{Pos: c.Pos(), Directive: d.name})
 				}
+				continue
+			}
+
+			entry := &Entry{
+				pos:       c.Pos(),
+				directive: d.name,
+				checkers:  d.checkers,
+				reason:    d.reason,
+				used:      make(map[CheckerName]bool),
+			}
+			for _, name := range d.checkers {
+				if !IsKnown(name) {
+					entry.unknown = append(entry.unknown, name)
+				}
+			}
+			m.entries = append(m.entries, entry)
+
+			switch d.kind {
+			case kindLine:
+				m.lines[line] = entry
+				if fn, ok := funcDocs[cg]; ok {
+					m.regions = append(m.regions, region{
+						entry: entry,
+						start: fset.Position(fn.Pos()).Line,
+						end:   fset.Position(fn.End()).Line,
+					})
+				}
+			case kindBegin:
+				open = append(open, region{entry: entry, start: line})
+			case kindFile:
+				m.regions = append(m.regions, region{entry: entry, start: 1, end: fileEnd})
 			}
 		}
 	}
 
+	// Unterminated ignore-begin covers the rest of the file
+	for _, r := range open {
+		r.end = fileEnd
+		m.regions = append(m.regions, r)
+		m.unmatched = append(m.unmatched, UnmatchedRegion{Pos: r.entry.pos, Directive: r.entry.directive})
+	}
+	slices.SortFunc(m.unmatched, func(a, b UnmatchedRegion) int {
+		return cmp.Compare(a.Pos, b.Pos)
+	})
+
 	return m
 }
 
-// parseComment parses an ignore directive and returns the checker names.
-// Returns nil slice if no specific checkers are specified (ignore all).
+// parseComment parses an ignore directive.
+// The checker list is nil if no specific checkers are specified (ignore all).
 // Returns false if not an ignore comment.
-func parseComment(text string) ([]CheckerName, bool) {
+func parseComment(text string) (directive, bool) {
 	text = strings.TrimPrefix(text, "//")
 	text = strings.TrimSpace(text)
 
-	if !strings.HasPrefix(text, "goroutinectx:ignore") {
-		return nil, false
+	var d directive
+	for _, p := range directivePrefixes {
+		rest, ok := strings.CutPrefix(text, p.name)
+		if !ok {
+			continue
+		}
+		// Reject longer unknown words such as "goroutinectx:ignored"
+		if rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+			continue
+		}
+		d.name, d.kind, text = p.name, p.kind, rest
+		break
+	}
+	if d.name == "" {
+		return directive{}, false
 	}
 
-	// Extract checker names after "goroutinectx:ignore"
-	rest := strings.TrimPrefix(text, "goroutinectx:ignore")
-	rest = strings.TrimSpace(rest)
+	rest := strings.TrimSpace(text)
 
-	if rest == "" {
-		return nil, true // No specific checkers = ignore all
+	// Stop at trailing comments: "//" or " //"
+	if strings.HasPrefix(rest, "//") {
+		rest = ""
+	} else if idx := strings.Index(rest, " //"); idx >= 0 {
+		rest = rest[:idx]
 	}
 
-	// Stop at comment markers: " - ", " // ", or " //"
+	// Extract the reason: " - reason", or "- reason" when no checkers are specified
 	if idx := strings.Index(rest, " - "); idx >= 0 {
+		d.reason = strings.TrimSpace(rest[idx+3:])
 		rest = rest[:idx]
-	}
-	if idx := strings.Index(rest, " //"); idx >= 0 {
-		rest = rest[:idx]
-	}
-	// Handle "- " at the start (no checkers specified, just comment)
-	if strings.HasPrefix(rest, "- ") || rest == "-" {
-		return nil, true
+	} else if after, ok := strings.CutPrefix(rest, "- "); ok {
+		d.reason = strings.TrimSpace(after)
+		rest = ""
+	} else if rest == "-" {
+		rest = ""
 	}
 
 	rest = strings.TrimSpace(rest)
 	if rest == "" {
-		return nil, true
+		return d, true // No specific checkers = ignore all
 	}
 
 	// Parse comma-separated checker names
 	parts := strings.Split(rest, ",")
-	checkers := make([]CheckerName, 0, len(parts))
+	d.checkers = make([]CheckerName, 0, len(parts))
 
 	for _, part := range parts {
 		name := CheckerName(strings.TrimSpace(part))
 		if name != "" {
-			checkers = append(checkers, name)
+			d.checkers = append(d.checkers, name)
 		}
 	}
 
-	return checkers, true
+	return d, true
 }
 
 // ShouldIgnore returns true if the given line should be ignored for the specified checker.
 func (m Map) ShouldIgnore(line int, checker CheckerName) bool {
-	if m.shouldIgnoreEntry(m[line], checker) {
+	if m.shouldIgnoreEntry(m.lines[line], checker) {
 		return true
 	}
-	if m.shouldIgnoreEntry(m[line-1], checker) {
+	if m.shouldIgnoreEntry(m.lines[line-1], checker) {
 		return true
 	}
 
+	for _, r := range m.regions {
+		if r.start <= line && line <= r.end && m.shouldIgnoreEntry(r.entry, checker) {
+			return true
+		}
+	}
+
 	return false
 }
 
@@ -128,9 +268,9 @@ func (m Map) shouldIgnoreEntry(entry *Entry, checker CheckerName) bool {
 		return true
 	}
 
-	// Check if the specified checker is in the list
+	// Check if the specified checker (or an alias covering it) is in the list
 	for _, c := range entry.checkers {
-		if c == checker {
+		if covers(c, checker) {
 			entry.used[checker] = true
 			return true
 		}
@@ -139,6 +279,16 @@ func (m Map) shouldIgnoreEntry(entry *Entry, checker CheckerName) bool {
 	return false
 }
 
+// usedBy reports whether the directive name suppressed any checker it covers.
+func (e *Entry) usedBy(name CheckerName) bool {
+	for checker := range e.used {
+		if covers(name, checker) {
+			return true
+		}
+	}
+	return false
+}
+
 // UnusedIgnore represents an unused ignore directive.
 type UnusedIgnore struct {
 	Pos      token.Pos
@@ -149,7 +299,7 @@ type UnusedIgnore struct {
 func (m Map) GetUnusedIgnores(enabled EnabledCheckers) []UnusedIgnore {
 	var unused []UnusedIgnore
 
-	for _, entry := range m {
+	for _, entry := range m.entries {
 		if len(entry.checkers) == 0 {
 			// Ignore-all directive: check if any enabled checker used it
 			anyUsed := false
@@ -166,11 +316,12 @@ func (m Map) GetUnusedIgnores(enabled EnabledCheckers) []UnusedIgnore {
 			// Specific checkers: report each unused one
 			var unusedCheckers []CheckerName
 			for _, checker := range entry.checkers {
-				if !enabled[checker] {
-					// Checker is not enabled - report as invalid
-					unusedCheckers = append(unusedCheckers, checker)
-				} else if !entry.used[checker] {
-					// Checker is enabled but wasn't used
+				if !IsKnown(checker) {
+					// Unknown names are reported by GetUnknownCheckers
+					continue
+				}
+				if !entry.usedBy(checker) {
+					// Checker is not enabled, or is enabled but wasn't used
 					unusedCheckers = append(unusedCheckers, checker)
 				}
 			}
@@ -185,3 +336,59 @@ func (m Map) GetUnusedIgnores(enabled EnabledCheckers) []UnusedIgnore {
 
 	return unused
 }
+
+// MissingReason represents an ignore directive without a " - reason" text.
+type MissingReason struct {
+	Pos       token.Pos
+	Directive string
+}
+
+// GetMissingReasons returns ignore directives that do not explain why they are needed.
+func (m Map) GetMissingReasons() []MissingReason {
+	var missing []MissingReason
+
+	for _, entry := range m.entries {
+		if entry.reason == "" {
+			missing = append(missing, MissingReason{Pos: entry.pos, Directive: entry.directive})
+		}
+	}
+
+	return missing
+}
+
+// UnmatchedRegion represents an ignore-begin directive without a matching
+// ignore-end, or an ignore-end directive without a matching ignore-begin.
+type UnmatchedRegion struct {
+	Pos       token.Pos
+	Directive string
+}
+
+// GetUnmatchedRegions returns the unterminated ignore-begin and stray
+// ignore-end directives in source order.
+func (m Map) GetUnmatchedRegions() []UnmatchedRegion {
+	return m.unmatched
+}
+
+// UnknownChecker represents a checker name in an ignore directive that is not registered.
+type UnknownChecker struct {
+	Pos        token.Pos
+	Name       CheckerName
+	Suggestion CheckerName // Closest registered name (empty if none is close)
+}
+
+// GetUnknownCheckers returns checker names that do not match any registered checker.
+func (m Map) GetUnknownCheckers() []UnknownChecker {
+	var unknown []UnknownChecker
+
+	for _, entry := range m.entries {
+		for _, name := range entry.unknown {
+			u := UnknownChecker{Pos: entry.pos, Name: name}
+			if suggestion, ok := Suggest(name); ok {
+				u.Suggestion = suggestion
+			}
+			unknown = append(unknown, u)
+		}
+	}
+
+	return unknown
+}
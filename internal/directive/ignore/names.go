@@ -0,0 +1,89 @@
+package ignore
+
+// knownCheckers lists every checker name accepted by ignore directives.
+var knownCheckers = []CheckerName{
+	Goroutine,
+	GoroutineDerive,
+	Waitgroup,
+	Errgroup,
+	Conc,
+	Spawner,
+	Spawnerlabel,
+	Gotask,
+	Admission,
+	Iterator,
+	Handler,
+	Ctxfield,
+	Stdlib,
+	LoopCancel,
+}
+
+// aliases maps a checker name to other checkers it also covers.
+// errgroup covered conc findings before conc had its own name.
+var aliases = map[CheckerName][]CheckerName{
+	Errgroup: {Conc},
+}
+
+// IsKnown reports whether the name is a registered checker name.
+func IsKnown(name CheckerName) bool {
+	for _, known := range knownCheckers {
+		if known == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Suggest returns the registered checker name closest to an unknown name.
+// Returns false if no registered name is close enough to be a likely typo.
+func Suggest(name CheckerName) (CheckerName, bool) {
+	const maxDistance = 2
+
+	var best CheckerName
+	bestDistance := maxDistance + 1
+
+	for _, known := range knownCheckers {
+		if d := editDistance(string(name), string(known)); d < bestDistance {
+			best, bestDistance = known, d
+		}
+	}
+
+	return best, bestDistance <= maxDistance
+}
+
+// covers reports whether a directive naming name suppresses the checker.
+func covers(name, checker CheckerName) bool {
+	if name == checker {
+		return true
+	}
+	for _, alias := range aliases[name] {
+		if alias == checker {
+			return true
+		}
+	}
+	return false
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
@@ -18,11 +18,14 @@ type Map struct {
 }
 
 // IsSpawner checks if a function is marked as a spawner.
+// Instantiated generic functions and methods match by their origin.
 func (m *Map) IsSpawner(fn *types.Func) bool {
 	if m == nil {
 		return false
 	}
 
+	fn = fn.Origin()
+
 	if _, ok := m.local[fn]; ok {
 		return true
 	}
@@ -53,7 +56,7 @@ func (m *Map) matchesExternal(fn *types.Func) bool {
 func Build(pass *analysis.Pass, externalSpawners string) *Map {
 	m := &Map{
 		local:    make(map[*types.Func]struct{}),
-		external: parseExternal(externalSpawners),
+		external: funcspec.ParseList(externalSpawners),
 	}
 
 	for _, file := range pass.Files {
@@ -63,24 +66,6 @@ func Build(pass *analysis.Pass, externalSpawners string) *Map {
 	return m
 }
 
-// parseExternal parses the -external-spawner flag value.
-func parseExternal(s string) []funcspec.Spec {
-	if s == "" {
-		return nil
-	}
-
-	var specs []funcspec.Spec
-	for _, part := range strings.Split(s, ",") {
-		part = strings.TrimSpace(part)
-		if part == "" {
-			continue
-		}
-		specs = append(specs, funcspec.Parse(part))
-	}
-
-	return specs
-}
-
 // buildForFile scans a single file for spawner directives.
 func buildForFile(pass *analysis.Pass, file *ast.File, m map[*types.Func]struct{}) {
 	lineComments := make(map[int]string)
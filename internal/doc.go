@@ -33,20 +33,21 @@
 //
 // # Checker Types
 //
-// There are two primary checker interfaces:
+// There are three checker interfaces:
 //
 //   - [GoStmtChecker]: Checks go statements (e.g., `go func() { ... }()`)
 //   - [CallChecker]: Checks function call expressions (e.g., `g.Go(func() { ... })`)
+//   - [RangeStmtChecker]: Checks range-over-func loops (e.g., `for x := range parallel.Each(xs)`)
 //
 // Example checker registration:
 //
 //	goStmtCheckers := []GoStmtChecker{
 //	    &checkers.Goroutine{},
-//	    checkers.NewGoroutineDerive(deriveMatcher),
+//	    checkers.NewGoroutineDerive(deriverRules.ForChecker(ignore.Goroutine), requireUsed),
 //	}
 //	callCheckers := []CallChecker{
-//	    checkers.NewErrgroupChecker(deriveMatcher),
-//	    checkers.NewWaitgroupChecker(deriveMatcher),
+//	    checkers.NewErrgroupChecker(deriverRules, requireUsed),
+//	    checkers.NewWaitgroupChecker(deriverRules, requireUsed),
 //	}
 //
 // # Execution Flow
@@ -57,6 +58,7 @@
 //  4. For each node in a context-aware scope:
 //     - go statements -> [GoStmtChecker.CheckGoStmt]
 //     - call expressions -> [CallChecker.CheckCall]
+//     - range statements -> [RangeStmtChecker.CheckRangeStmt]
 //  5. Results are reported via pass.Reportf
 //
 // # Result Handling
@@ -0,0 +1,52 @@
+// Package facts infers facts about the functions of a package and exports
+// them as [analysis.Fact]s, so that packages importing it can use them.
+//
+// # Deriver Wrappers
+//
+// Teams often wrap the configured -goroutine-deriver:
+//
+//	func startSpan(ctx context.Context, name string) (context.Context, func()) {
+//	    ctx = apm.NewGoroutineContext(ctx)
+//	    ...
+//	    return ctx, end
+//	}
+//
+// A function with a context.Context parameter whose first context.Context
+// result is derived from that parameter by calls, on every return path, gets
+// a [deriver.Fact] listing those calls. Calls to wrappers, in the same package
+// or imported, contribute the calls recorded in their facts.
+//
+// Calling a wrapper then satisfies any deriver it calls:
+//
+//	go func() {
+//	    ctx, end := startSpan(ctx, "worker")  // OK: calls apm.NewGoroutineContext
+//	    defer end()
+//	    doWork(ctx)
+//	}()
+//
+// A function that returns its context parameter unchanged on some path is
+// not a wrapper, since the deriver is not always called.
+//
+// # Deriver Directives
+//
+// A function marked with //goroutinectx:deriver gets a [deriver.DirectiveFact],
+// so that goroutines in the packages importing it must call it as well.
+// Only the package declaring it and its direct importers require it; a package
+// depending on it only transitively does not.
+//
+// # Carrier Directives
+//
+// A type marked with //goroutinectx:carrier gets a [carrier.Fact], so that it
+// is a context carrier in the packages importing it as well. A type naming an
+// invalid accessor gets none.
+//
+// # Result
+//
+// [Analyzer] runs on the analyzed packages and all of their dependencies.
+// Its [Result] merges the facts of the package with the imported ones:
+//
+//	facts := pass.ResultOf[facts.Analyzer].(*facts.Result)
+//	derivers.AddDerivers(facts.Derivers)
+//	derivers.SetWrappers(facts.Wrappers)
+//	carriers = append(carriers, facts.Carriers...)
+package facts
@@ -0,0 +1,180 @@
+// Package facts infers facts about functions and shares them with dependent packages.
+package facts
+
+import (
+	"go/ast"
+	"go/types"
+	"reflect"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/mpyw/goroutinectx/internal/deriver"
+	"github.com/mpyw/goroutinectx/internal/directive/carrier"
+	deriverdirective "github.com/mpyw/goroutinectx/internal/directive/deriver"
+	"github.com/mpyw/goroutinectx/internal/funcspec"
+	"github.com/mpyw/goroutinectx/internal/ssa"
+	"github.com/mpyw/goroutinectx/internal/typeutil"
+)
+
+// Result holds the facts of a package and its dependencies.
+type Result struct {
+	// Wrappers maps functions returning a context derived from their context
+	// parameter to the functions they call to derive it (see [deriver.Fact]).
+	Wrappers map[*types.Func][]funcspec.Spec
+
+	// Derivers are the functions declared as derivers with the
+	// //goroutinectx:deriver directive (see [deriver.DirectiveFact]) in the
+	// package or in the packages it directly imports.
+	Derivers []funcspec.Spec
+
+	// Carriers are the types declared as context carriers with the
+	// //goroutinectx:carrier directive (see [carrier.Fact]).
+	Carriers []carrier.Carrier
+}
+
+// Analyzer exports a [deriver.Fact] for every function of a package that
+// derives the context it returns from its context parameter, and a
+// [deriver.DirectiveFact] for every function marked with //goroutinectx:deriver
+// and a [carrier.Fact] for every type marked with //goroutinectx:carrier.
+// It collects the facts of the package and its dependencies as its result (*Result).
+// It reports no diagnostics.
+var Analyzer = &analysis.Analyzer{
+	Name:       "goroutinectxfacts",
+	Doc:        "infers context deriver wrappers, collects //goroutinectx:deriver and //goroutinectx:carrier directives and exports them as facts",
+	Run:        run,
+	FactTypes:  []analysis.Fact{new(deriver.Fact), new(deriver.DirectiveFact), new(carrier.Fact)},
+	ResultType: reflect.TypeFor[*Result](),
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	result := &Result{
+		Wrappers: make(map[*types.Func][]funcspec.Spec),
+	}
+
+	for _, fn := range deriverdirective.Build(pass) {
+		pass.ExportObjectFact(fn, &deriver.DirectiveFact{})
+	}
+
+	for _, d := range carrier.Build(pass) {
+		// Invalid accessors are reported by the main analyzer; importers must not see them
+		if !d.ValidAccessor() {
+			continue
+		}
+		pass.ExportObjectFact(d.Type, &carrier.Fact{Accessor: d.Accessor})
+	}
+
+	for _, fact := range pass.AllObjectFacts() {
+		switch f := fact.Fact.(type) {
+		case *deriver.Fact:
+			if fn, ok := fact.Object.(*types.Func); ok {
+				result.Wrappers[fn] = f.Derives
+			}
+		case *deriver.DirectiveFact:
+			if fn, ok := fact.Object.(*types.Func); ok && imports(pass.Pkg, fn.Pkg()) {
+				result.Derivers = append(result.Derivers, funcspec.Of(fn))
+			}
+		case *carrier.Fact:
+			if obj := fact.Object; obj.Pkg() != nil {
+				result.Carriers = append(result.Carriers, carrier.Carrier{
+					PkgPath:  obj.Pkg().Path(),
+					TypeName: obj.Name(),
+					Accessor: f.Accessor,
+				})
+			}
+		}
+	}
+
+	// Facts are unordered; keep matcher groups and messages deterministic
+	slices.SortFunc(result.Derivers, func(a, b funcspec.Spec) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	slices.SortFunc(result.Carriers, func(a, b carrier.Carrier) int {
+		return strings.Compare(a.PkgPath+"."+a.TypeName, b.PkgPath+"."+b.TypeName)
+	})
+
+	for fn, derives := range inferWrappers(pass, result.Wrappers) {
+		result.Wrappers[fn] = derives
+		pass.ExportObjectFact(fn, &deriver.Fact{Derives: derives})
+	}
+
+	return result, nil
+}
+
+// imports checks if pkg is dep itself or imports it directly. Derivers declared
+// deeper in the dependency graph are not required, since the package cannot call them.
+func imports(pkg, dep *types.Package) bool {
+	return pkg == dep || slices.Contains(pkg.Imports(), dep)
+}
+
+// inferWrappers finds the functions declared in the package that derive the
+// context they return. Wrappers calling other wrappers of the package are
+// found by repeating until nothing changes.
+// The package is only built in SSA form if it declares a candidate function.
+func inferWrappers(pass *analysis.Pass, imported map[*types.Func][]funcspec.Spec) map[*types.Func][]funcspec.Spec {
+	if !declaresCandidate(pass) {
+		return nil
+	}
+
+	prog := ssa.BuildPackage(pass)
+
+	known := make(map[*types.Func][]funcspec.Spec, len(imported))
+	for fn, derives := range imported {
+		known[fn] = derives
+	}
+
+	local := make(map[*types.Func][]funcspec.Spec)
+
+	for changed := true; changed; {
+		changed = false
+
+		for _, ssaFn := range prog.SrcFuncs {
+			fn, ok := ssaFn.Object().(*types.Func)
+			if !ok || fn.Pkg() != pass.Pkg {
+				continue
+			}
+
+			derives := ssa.DerivedContext(ssaFn, known)
+			if len(derives) > len(local[fn]) {
+				local[fn] = derives
+				known[fn] = derives
+				changed = true
+			}
+		}
+	}
+
+	return local
+}
+
+// declaresCandidate checks if the package declares a function with both a
+// context.Context parameter and a context.Context result.
+func declaresCandidate(pass *analysis.Pass) bool {
+	for _, file := range pass.Files {
+		for _, decl := range file.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok {
+				continue
+			}
+			fn, ok := pass.TypesInfo.Defs[funcDecl.Name].(*types.Func)
+			if !ok {
+				continue
+			}
+			sig := fn.Type().(*types.Signature)
+			if hasContext(sig.Params()) && hasContext(sig.Results()) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasContext checks if any variable of the tuple is a context.Context.
+func hasContext(tuple *types.Tuple) bool {
+	for v := range tuple.Variables() {
+		if typeutil.IsContextType(v.Type()) {
+			return true
+		}
+	}
+	return false
+}
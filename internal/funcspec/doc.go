@@ -78,4 +78,9 @@
 //   - Direct calls: pkg.Func()
 //   - Method calls: obj.Method()
 //   - Interface method calls
+//   - Generic calls: Func[T](), pkg.Func[K, V](), obj.Method() on Type[T]
+//
+// Generic functions and methods are always returned as their origin,
+// so [Spec.Matches] and identity comparisons are independent of
+// type arguments.
 package funcspec
@@ -48,6 +48,32 @@ func Parse(s string) Spec {
 	return spec
 }
 
+// ParseList parses a comma-separated list of function specifications.
+func ParseList(s string) []Spec {
+	if s == "" {
+		return nil
+	}
+
+	var specs []Spec
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		specs = append(specs, Parse(part))
+	}
+
+	return specs
+}
+
+// String returns the specification in the format accepted by [Parse].
+func (s Spec) String() string {
+	if s.TypeName != "" {
+		return s.PkgPath + "." + s.TypeName + "." + s.FuncName
+	}
+	return s.PkgPath + "." + s.FuncName
+}
+
 // FullName returns the full API name for message formatting.
 func (s Spec) FullName() string {
 	shortPkg := shortPkgName(s.PkgPath)
@@ -58,7 +84,10 @@ func (s Spec) FullName() string {
 }
 
 // Matches checks if a types.Func matches this specification.
+// Instantiated generic functions and methods match by their origin.
 func (s Spec) Matches(fn *types.Func) bool {
+	fn = fn.Origin()
+
 	if fn.Name() != s.FuncName {
 		return false
 	}
@@ -92,25 +121,54 @@ func (s Spec) Matches(fn *types.Func) bool {
 	return named.Obj().Name() == s.TypeName
 }
 
+// Of returns the specification of a function, naming its origin and,
+// for methods, the receiver's named type.
+func Of(fn *types.Func) Spec {
+	fn = fn.Origin()
+
+	spec := Spec{FuncName: fn.Name()}
+	if pkg := fn.Pkg(); pkg != nil {
+		spec.PkgPath = pkg.Path()
+	}
+
+	if recv := fn.Type().(*types.Signature).Recv(); recv != nil {
+		if named, ok := typeutil.UnwrapPointer(recv.Type()).(*types.Named); ok {
+			spec.TypeName = named.Obj().Name()
+		}
+	}
+
+	return spec
+}
+
+// MatchesSpec checks if the specification of a function (see [Of]) matches this specification.
+func (s Spec) MatchesSpec(other Spec) bool {
+	return other.FuncName == s.FuncName &&
+		other.TypeName == s.TypeName &&
+		matchPkg(other.PkgPath, s.PkgPath)
+}
+
 // ExtractFunc extracts the types.Func from a call expression.
+// For generic functions and methods on generic types, the origin
+// (uninstantiated) function is returned, so that it can be compared
+// by identity with declarations regardless of instantiation.
 func ExtractFunc(pass *analysis.Pass, call *ast.CallExpr) *types.Func {
-	switch fun := call.Fun.(type) {
+	switch fun := unwrapInstance(call.Fun).(type) {
 	case *ast.Ident:
 		obj := pass.TypesInfo.ObjectOf(fun)
 		if f, ok := obj.(*types.Func); ok {
-			return f
+			return f.Origin()
 		}
 
 	case *ast.SelectorExpr:
 		sel := pass.TypesInfo.Selections[fun]
 		if sel != nil {
 			if f, ok := sel.Obj().(*types.Func); ok {
-				return f
+				return f.Origin()
 			}
 		} else {
 			obj := pass.TypesInfo.ObjectOf(fun.Sel)
 			if f, ok := obj.(*types.Func); ok {
-				return f
+				return f.Origin()
 			}
 		}
 	}
@@ -118,6 +176,23 @@ func ExtractFunc(pass *analysis.Pass, call *ast.CallExpr) *types.Func {
 	return nil
 }
 
+// unwrapInstance strips parentheses and explicit type arguments
+// (Spawn[int], pkg.Spawn[K, V]) from a call target.
+func unwrapInstance(expr ast.Expr) ast.Expr {
+	for {
+		switch e := expr.(type) {
+		case *ast.ParenExpr:
+			expr = e.X
+		case *ast.IndexExpr:
+			expr = e.X
+		case *ast.IndexListExpr:
+			expr = e.X
+		default:
+			return expr
+		}
+	}
+}
+
 // shortPkgName returns the last component of a package path.
 func shortPkgName(pkgPath string) string {
 	if idx := strings.LastIndex(pkgPath, "/"); idx >= 0 {
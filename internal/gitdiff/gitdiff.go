@@ -0,0 +1,190 @@
+// Package gitdiff computes lines changed since a git revision using the local git command.
+package gitdiff
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Changes holds the lines added or modified since a revision, keyed by absolute filename.
+// A nil *Changes contains every line (no filtering).
+type Changes struct {
+	files map[string]fileChanges
+
+	mu       sync.Mutex
+	resolved map[string]string // filename → symlink-resolved filename
+}
+
+// fileChanges holds the changed lines of a single file.
+type fileChanges struct {
+	all   bool // Untracked file: every line is new
+	lines map[int]bool
+}
+
+// cache shares Changes between packages of the same repository.
+var (
+	cacheMu sync.Mutex
+	cache   = make(map[[2]string]*Changes)
+)
+
+// Load returns the lines changed in the working tree since rev, for the
+// git repository containing dir. Results are cached per repository and revision.
+func Load(dir, rev string) (*Changes, error) {
+	top, err := git(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+	top = resolve(strings.TrimSpace(top))
+
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	key := [2]string{top, rev}
+	if c, ok := cache[key]; ok {
+		return c, nil
+	}
+
+	diff, err := git(top, "diff", "--unified=0", "--no-color", "--no-ext-diff", "--no-renames", "--src-prefix=a/", "--dst-prefix=b/", rev, "--")
+	if err != nil {
+		return nil, err
+	}
+
+	untracked, err := git(top, "ls-files", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Changes{
+		files:    parseDiff(top, diff),
+		resolved: make(map[string]string),
+	}
+	for name := range strings.SplitSeq(untracked, "\n") {
+		if name != "" {
+			c.files[filepath.Join(top, name)] = fileChanges{all: true}
+		}
+	}
+
+	cache[key] = c
+	return c, nil
+}
+
+// Contains reports whether the line of the file was changed since the revision.
+func (c *Changes) Contains(filename string, line int) bool {
+	if c == nil {
+		return true
+	}
+
+	fc, ok := c.files[c.resolve(filename)]
+	if !ok {
+		return false
+	}
+
+	return fc.all || fc.lines[line]
+}
+
+// resolve returns the symlink-resolved absolute filename, cached.
+func (c *Changes) resolve(filename string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if r, ok := c.resolved[filename]; ok {
+		return r
+	}
+
+	r := resolve(filename)
+	c.resolved[filename] = r
+	return r
+}
+
+// parseDiff parses "git diff --unified=0" output into changed lines of the new files.
+func parseDiff(top, diff string) map[string]fileChanges {
+	files := make(map[string]fileChanges)
+
+	var current map[int]bool
+	sc := bufio.NewScanner(strings.NewReader(diff))
+	sc.Buffer(nil, 1024*1024)
+
+	for sc.Scan() {
+		line := sc.Text()
+
+		switch {
+		case strings.HasPrefix(line, "+++ "):
+			name := strings.TrimPrefix(line, "+++ ")
+			if name == "/dev/null" {
+				current = nil // Deleted file
+				continue
+			}
+			current = make(map[int]bool)
+			files[filepath.Join(top, strings.TrimPrefix(name, "b/"))] = fileChanges{lines: current}
+
+		case strings.HasPrefix(line, "@@ ") && current != nil:
+			start, count, ok := parseHunk(line)
+			if !ok {
+				continue
+			}
+			for l := start; l < start+count; l++ {
+				current[l] = true
+			}
+		}
+	}
+
+	return files
+}
+
+// parseHunk extracts the new-file range from a hunk header such as "@@ -1,2 +3,4 @@".
+func parseHunk(header string) (start, count int, ok bool) {
+	fields := strings.Fields(header)
+	if len(fields) < 3 || !strings.HasPrefix(fields[2], "+") {
+		return 0, 0, false
+	}
+
+	rng := strings.TrimPrefix(fields[2], "+")
+	startStr, countStr, hasCount := strings.Cut(rng, ",")
+
+	start, err := strconv.Atoi(startStr)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	count = 1
+	if hasCount {
+		if count, err = strconv.Atoi(countStr); err != nil {
+			return 0, 0, false
+		}
+	}
+
+	return start, count, true
+}
+
+// git runs a git command in dir and returns its standard output.
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-c", "core.quotepath=off", "-C", dir}, args...)...)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+
+	return string(out), nil
+}
+
+// resolve returns the absolute, symlink-resolved form of path,
+// or the cleaned path if it cannot be resolved.
+func resolve(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	if r, err := filepath.EvalSymlinks(path); err == nil {
+		return r
+	}
+	return filepath.Clean(path)
+}
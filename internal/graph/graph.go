@@ -0,0 +1,540 @@
+// Package graph collects the spawn sites of a package for the context propagation graph report.
+package graph
+
+import (
+	"errors"
+	"flag"
+	"go/ast"
+	"go/types"
+	"reflect"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	gossa "golang.org/x/tools/go/ssa"
+
+	"github.com/mpyw/goroutinectx/internal"
+	"github.com/mpyw/goroutinectx/internal/config"
+	"github.com/mpyw/goroutinectx/internal/deriver"
+	"github.com/mpyw/goroutinectx/internal/directive/carrier"
+	"github.com/mpyw/goroutinectx/internal/directive/ignore"
+	"github.com/mpyw/goroutinectx/internal/directive/spawner"
+	"github.com/mpyw/goroutinectx/internal/facts"
+	"github.com/mpyw/goroutinectx/internal/funcspec"
+	"github.com/mpyw/goroutinectx/internal/probe"
+	"github.com/mpyw/goroutinectx/internal/registry"
+	"github.com/mpyw/goroutinectx/internal/scope"
+	"github.com/mpyw/goroutinectx/internal/ssa"
+)
+
+// Site is a single place where a goroutine is spawned.
+type Site struct {
+	Pos             string `json:"pos"`
+	Package         string `json:"package"`
+	Func            string `json:"func"`                    // Enclosing function (SSA name, e.g., "pkg.F$1")
+	API             string `json:"api"`                     // "go", the spawn API (e.g., "golang.org/x/sync/errgroup.Group.Go") or the concurrent iterator
+	Checker         string `json:"checker"`                 // Checker responsible for the site
+	Callee          string `json:"callee"`                  // Spawned function (the loop body for a concurrent iterator)
+	Context         string `json:"context,omitempty"`       // Context variable in scope, if any
+	CapturesContext bool   `json:"captures_context"`        // Spawned function uses the context
+	CallsDeriver    *bool  `json:"calls_deriver,omitempty"` // nil if no deriver is configured for the site
+	Detached        bool   `json:"detached,omitempty"`      // Spawned function uses a -detach-funcs context
+	Ignored         bool   `json:"ignored,omitempty"`       // Suppressed by a //goroutinectx:ignore directive
+}
+
+// cfg holds the flags of the graph analyzer, shared with the main analyzer.
+var cfg config.Config
+
+func init() {
+	cfg.RegisterFlags(&Analyzer.Flags)
+}
+
+// Analyzer collects the spawn sites of each package as its result ([]Site).
+// It reports no diagnostics.
+var Analyzer = &analysis.Analyzer{
+	Name:       "goroutinectxgraph",
+	Doc:        "collects goroutine spawn sites and how context flows into them",
+	Requires:   []*analysis.Analyzer{inspect.Analyzer, ssa.BuildSSAAnalyzer, facts.Analyzer},
+	Run:        run,
+	Flags:      flag.FlagSet{},
+	ResultType: reflect.TypeFor[[]Site](),
+}
+
+var ErrNoInspector = errors.New("inspector analyzer result not found")
+
+var ErrNoFacts = errors.New("facts analyzer result not found")
+
+// spawnAPI is a registry of spawn APIs handled by a single checker.
+type spawnAPI struct {
+	checker ignore.CheckerName
+	reg     *registry.Registry
+}
+
+// collector gathers the spawn sites of a pass.
+type collector struct {
+	pass      *analysis.Pass
+	prog      *ssa.Program
+	tracer    *ssa.Tracer
+	carriers  []carrier.Carrier
+	detachers []funcspec.Spec
+	iterators []funcspec.Spec
+	fields    bool
+	spawners  *spawner.Map
+	derivers  *deriver.Rules
+	apis      []spawnAPI
+	ignores   map[string]ignore.Map
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	insp, ok := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	if !ok {
+		return nil, ErrNoInspector
+	}
+
+	factsResult, ok := pass.ResultOf[facts.Analyzer].(*facts.Result)
+	if !ok {
+		return nil, ErrNoFacts
+	}
+
+	derivers, err := cfg.Derivers(factsResult)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &collector{
+		pass:      pass,
+		prog:      ssa.Build(pass),
+		tracer:    ssa.NewTracer(),
+		carriers:  cfg.Carriers(pass, factsResult),
+		detachers: cfg.Detachers(),
+		iterators: cfg.Iterators(),
+		fields:    cfg.ContextFields,
+		spawners:  cfg.Spawners(pass),
+		derivers:  derivers,
+		apis: []spawnAPI{
+			{ignore.Errgroup, newRegistry(internal.RegisterErrgroupAPIs)},
+			{ignore.Waitgroup, newRegistry(internal.RegisterWaitgroupAPIs)},
+			{ignore.Conc, newRegistry(internal.RegisterConcAPIs)},
+			{ignore.Gotask, newRegistry(internal.RegisterGotaskAPIs)},
+		},
+		ignores: make(map[string]ignore.Map),
+	}
+
+	for _, file := range pass.Files {
+		c.ignores[pass.Fset.Position(file.Pos()).Filename] = ignore.Build(pass.Fset, file)
+	}
+
+	return c.collect(insp), nil
+}
+
+// newRegistry creates a registry populated by register.
+func newRegistry(register func(*registry.Registry)) *registry.Registry {
+	reg := registry.New()
+	register(reg)
+	return reg
+}
+
+// collect walks every go statement, spawn API call and concurrent iterator loop
+// of the package in source order.
+func (c *collector) collect(insp *inspector.Inspector) []Site {
+	scopes := scope.Build(c.pass, insp, c.carriers, c.fields, scope.Spawns{
+		APIs:     newRegistry(internal.RegisterSpawnAPIs),
+		Spawners: c.spawners,
+	})
+
+	var sites []Site
+
+	nodeFilter := []ast.Node{
+		(*ast.FuncDecl)(nil),
+		(*ast.FuncLit)(nil),
+		(*ast.GoStmt)(nil),
+		(*ast.CallExpr)(nil),
+		(*ast.RangeStmt)(nil),
+	}
+
+	insp.WithStack(nodeFilter, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+
+		cctx := &probe.Context{
+			Pass:          c.pass,
+			Tracer:        c.tracer,
+			SSAProg:       c.prog,
+			Carriers:      c.carriers,
+			Detachers:     c.detachers,
+			ContextFields: c.fields,
+		}
+		if s := scope.FindEnclosing(scopes, stack); s != nil {
+			cctx.CtxNames = s.CtxNames
+		}
+
+		switch node := n.(type) {
+		case *ast.GoStmt:
+			sites = append(sites, c.goStmtSite(cctx, node))
+		case *ast.CallExpr:
+			sites = append(sites, c.callSites(cctx, node)...)
+		case *ast.RangeStmt:
+			if site, ok := c.rangeSite(cctx, node); ok {
+				sites = append(sites, site)
+			}
+		}
+
+		return true
+	})
+
+	return sites
+}
+
+// goStmtSite describes a go statement.
+func (c *collector) goStmtSite(cctx *probe.Context, stmt *ast.GoStmt) Site {
+	call := stmt.Call
+	matcher := c.derivers.ForChecker(ignore.Goroutine)
+
+	// go func() { ... }()
+	if lit, ok := call.Fun.(*ast.FuncLit); ok {
+		return c.site(cctx, stmt, "go", ignore.Goroutine, lit, matcher)
+	}
+
+	// go worker(ctx) runs a named function with the given arguments
+	site := c.newSite(cctx, stmt, "go", ignore.Goroutine)
+	fn := funcspec.ExtractFunc(c.pass, call)
+	if fn != nil {
+		site.Callee = fn.FullName()
+	} else {
+		site.Callee = types.ExprString(call.Fun)
+	}
+
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		if assigns := cctx.FuncLitAssignmentsOfIdent(fun); len(assigns) > 0 {
+			site.CapturesContext = cctx.FuncLitsAllCaptureContext(assigns)
+		}
+	case *ast.CallExpr:
+		site.CapturesContext = cctx.FactoryCallReturnsContextUsingFunc(fun)
+	}
+	site.CapturesContext = site.CapturesContext || cctx.ArgsUseContext(call.Args)
+
+	if matcher != nil && !matcher.IsEmpty() {
+		called := c.funcCallsDeriver(fn, matcher)
+		site.CallsDeriver = &called
+	}
+
+	return site
+}
+
+// callSites describes the spawned arguments of a spawn API or spawner call.
+func (c *collector) callSites(cctx *probe.Context, call *ast.CallExpr) []Site {
+	fn := funcspec.ExtractFunc(c.pass, call)
+	if fn == nil {
+		return nil
+	}
+
+	for _, api := range c.apis {
+		match := api.reg.MatchFunc(fn)
+		if match == nil {
+			continue
+		}
+
+		matcher := c.derivers.ForCall(api.checker, fn)
+
+		// Task.DoAsync spawns its receiver
+		if match.AlwaysSpawns {
+			sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+			if !ok {
+				return nil
+			}
+			return []Site{c.site(cctx, call, match.FullName, api.checker, sel.X, matcher)}
+		}
+
+		var sites []Site
+		for _, arg := range spawnedArgs(fn, call, match.CallbackArgIdx) {
+			sites = append(sites, c.site(cctx, call, match.FullName, api.checker, arg, matcher))
+		}
+		return sites
+	}
+
+	if c.spawners.IsSpawner(fn) {
+		matcher := c.derivers.ForChecker(ignore.Spawner)
+
+		var sites []Site
+		for _, arg := range call.Args {
+			if typ := c.pass.TypesInfo.TypeOf(arg); typ != nil && isFunc(typ) {
+				sites = append(sites, c.site(cctx, call, fn.FullName(), ignore.Spawner, arg, matcher))
+			}
+		}
+		return sites
+	}
+
+	return nil
+}
+
+// rangeSite describes a range-over-func loop over a -concurrent-iterator function,
+// whose loop body runs on another goroutine.
+func (c *collector) rangeSite(cctx *probe.Context, stmt *ast.RangeStmt) (Site, bool) {
+	iterator := c.iteratorFunc(cctx, stmt.X)
+	if iterator == nil || stmt.Body == nil {
+		return Site{}, false
+	}
+
+	site := c.newSite(cctx, stmt, iterator.FullName(), ignore.Iterator)
+	matcher := c.derivers.ForChecker(ignore.Iterator)
+	hasDerivers := matcher != nil && !matcher.IsEmpty()
+
+	// The loop body is a synthetic yield function in SSA
+	if fn := c.prog.FindRangeFunc(stmt); fn != nil {
+		site.Callee = fn.String()
+		site.CapturesContext = cctx.ClosureCapturesContext(fn)
+		site.Detached = c.tracer.ClosureUsesDetachedContext(fn, c.detachers)
+		if hasDerivers {
+			called := c.tracer.ClosureCallsDeriver(fn, matcher).FoundAtStart
+			site.CallsDeriver = &called
+		}
+		return site, true
+	}
+
+	site.Callee = types.ExprString(stmt.X)
+	site.CapturesContext = cctx.BlockUsesContext(stmt.Body)
+	if hasDerivers {
+		called := matcher.SatisfiesAnyGroup(c.pass, stmt.Body)
+		site.CallsDeriver = &called
+	}
+	return site, true
+}
+
+// iteratorFunc returns the -concurrent-iterator function producing the range expression, or nil.
+// Supports direct calls (range parallel.Each(items)) and variables assigned from them.
+func (c *collector) iteratorFunc(cctx *probe.Context, x ast.Expr) *types.Func {
+	if len(c.iterators) == 0 {
+		return nil
+	}
+
+	x = ast.Unparen(x)
+	if typ := c.pass.TypesInfo.TypeOf(x); typ == nil || !isFunc(typ) {
+		return nil // Not range-over-func
+	}
+
+	call, ok := x.(*ast.CallExpr)
+	if !ok {
+		ident, ok := x.(*ast.Ident)
+		if !ok {
+			return nil
+		}
+		if call = cctx.CallExprAssignedToIdent(ident); call == nil {
+			return nil
+		}
+	}
+
+	fn := funcspec.ExtractFunc(c.pass, call)
+	if fn == nil {
+		return nil
+	}
+
+	for _, spec := range c.iterators {
+		if spec.Matches(fn) {
+			return fn
+		}
+	}
+	return nil
+}
+
+// isFunc reports whether the type is a function type.
+func isFunc(typ types.Type) bool {
+	_, ok := typ.Underlying().(*types.Signature)
+	return ok
+}
+
+// spawnedArgs returns the callback arguments of a spawn API call.
+// A variadic callback parameter spawns every remaining argument.
+func spawnedArgs(fn *types.Func, call *ast.CallExpr, idx int) []ast.Expr {
+	if idx >= len(call.Args) {
+		return nil
+	}
+
+	sig, ok := fn.Type().(*types.Signature)
+	if ok && sig.Variadic() && idx == sig.Params().Len()-1 && !call.Ellipsis.IsValid() {
+		return call.Args[idx:]
+	}
+
+	return call.Args[idx : idx+1]
+}
+
+// newSite creates a site with its position and enclosing function filled in.
+func (c *collector) newSite(cctx *probe.Context, node ast.Node, api string, checker ignore.CheckerName) Site {
+	position := c.pass.Fset.Position(node.Pos())
+
+	site := Site{
+		Pos:     position.String(),
+		Package: c.pass.Pkg.Path(),
+		API:     api,
+		Checker: string(checker),
+		Ignored: c.ignores[position.Filename].ShouldIgnore(position.Line, checker),
+	}
+
+	if len(cctx.CtxNames) > 0 {
+		site.Context = cctx.CtxNames[0]
+	}
+
+	if fn := c.enclosingFunc(node); fn != nil {
+		site.Func = fn.String()
+	} else {
+		site.Func = c.pass.Pkg.Path() + ".init"
+	}
+
+	return site
+}
+
+// site describes a spawned function expression.
+func (c *collector) site(cctx *probe.Context, node ast.Node, api string, checker ignore.CheckerName, spawned ast.Expr, matcher *deriver.Matcher) Site {
+	site := c.newSite(cctx, node, api, checker)
+	site.Callee = c.calleeName(spawned)
+	site.CapturesContext = c.capturesContext(cctx, spawned)
+
+	if lit, ok := ast.Unparen(spawned).(*ast.FuncLit); ok {
+		site.Detached = cctx.FuncLitUsesDetachedContext(lit)
+	}
+
+	if matcher != nil && !matcher.IsEmpty() {
+		called := c.callsDeriver(cctx, spawned, matcher)
+		site.CallsDeriver = &called
+	}
+
+	return site
+}
+
+// calleeName names the spawned function.
+func (c *collector) calleeName(expr ast.Expr) string {
+	expr = ast.Unparen(expr)
+
+	if lit, ok := expr.(*ast.FuncLit); ok {
+		if fn := c.prog.FindFuncLit(lit); fn != nil {
+			return fn.String()
+		}
+	}
+
+	if fn := c.namedFunc(expr); fn != nil {
+		return fn.FullName()
+	}
+
+	return types.ExprString(expr)
+}
+
+// capturesContext reports whether the spawned function uses context.
+// Uses SSA free variables for func literals when available, and the same
+// AST heuristics as the checkers otherwise.
+func (c *collector) capturesContext(cctx *probe.Context, expr ast.Expr) bool {
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.FuncLit:
+		if captured, ok := cctx.FuncLitCapturesContextSSA(e); ok {
+			return captured
+		}
+		return cctx.FuncLitCapturesContext(e)
+
+	case *ast.Ident:
+		assigns := cctx.FuncLitAssignmentsOfIdent(e)
+		return len(assigns) > 0 && cctx.FuncLitsAllCaptureContext(assigns)
+
+	case *ast.CallExpr:
+		return cctx.FactoryCallReturnsContextUsingFunc(e)
+
+	case *ast.SelectorExpr:
+		if c.namedFunc(e) != nil {
+			return false
+		}
+		return cctx.SelectorExprCapturesContext(e)
+
+	case *ast.IndexExpr:
+		return cctx.IndexExprCapturesContext(e)
+	}
+
+	return false
+}
+
+// callsDeriver reports whether the spawned function calls the deriver at its start.
+func (c *collector) callsDeriver(cctx *probe.Context, expr ast.Expr, matcher *deriver.Matcher) bool {
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.FuncLit:
+		if fn := c.prog.FindFuncLit(e); fn != nil {
+			return c.tracer.ClosureCallsDeriver(fn, matcher).FoundAtStart
+		}
+		return matcher.SatisfiesAnyGroup(c.pass, e.Body)
+
+	case *ast.Ident:
+		if assigns := cctx.FuncLitAssignmentsOfIdent(e); len(assigns) > 0 {
+			for _, assign := range assigns {
+				if !c.callsDeriver(cctx, assign.Lit, matcher) {
+					return false
+				}
+			}
+			return true
+		}
+	}
+
+	return c.funcCallsDeriver(c.namedFunc(expr), matcher)
+}
+
+// funcCallsDeriver reports whether a named function calls the deriver at its start.
+func (c *collector) funcCallsDeriver(fn *types.Func, matcher *deriver.Matcher) bool {
+	if fn == nil || c.prog == nil {
+		return false
+	}
+
+	ssaFn := c.prog.FuncValue(fn)
+	if ssaFn == nil || ssaFn.Blocks == nil {
+		return false
+	}
+
+	return c.tracer.ClosureCallsDeriver(ssaFn, matcher).FoundAtStart
+}
+
+// namedFunc returns the function or method an expression refers to, if any.
+func (c *collector) namedFunc(expr ast.Expr) *types.Func {
+	var ident *ast.Ident
+
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.Ident:
+		ident = e
+	case *ast.SelectorExpr:
+		ident = e.Sel
+	default:
+		return nil
+	}
+
+	fn, _ := c.pass.TypesInfo.Uses[ident].(*types.Func)
+	if fn == nil {
+		return nil
+	}
+
+	return fn.Origin()
+}
+
+// enclosingFunc returns the innermost SSA function containing the node.
+func (c *collector) enclosingFunc(node ast.Node) *gossa.Function {
+	if c.prog == nil {
+		return nil
+	}
+
+	fn := c.prog.FuncAt(node)
+	for fn != nil {
+		inner := innerFuncAt(fn, node)
+		if inner == nil {
+			return fn
+		}
+		fn = inner
+	}
+
+	return nil
+}
+
+// innerFuncAt returns the anonymous function of fn that contains the node.
+// The loop body of a range-over-func statement is not inside the statement
+// itself, so a loop is attributed to the function containing it.
+func innerFuncAt(fn *gossa.Function, node ast.Node) *gossa.Function {
+	for _, anon := range fn.AnonFuncs {
+		syntax := anon.Syntax()
+		if syntax != nil && syntax != node && syntax.Pos() <= node.Pos() && node.End() <= syntax.End() {
+			return anon
+		}
+	}
+	return nil
+}
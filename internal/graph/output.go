@@ -0,0 +1,98 @@
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// WriteJSON writes the sites as an indented JSON array.
+func WriteJSON(w io.Writer, sites []Site) error {
+	if sites == nil {
+		sites = []Site{}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(sites)
+}
+
+// WriteDOT writes the sites as a Graphviz digraph.
+// Nodes are functions; each edge is a spawn site from the enclosing function
+// to the spawned function. Edges that lose the context are drawn in red.
+func WriteDOT(w io.Writer, sites []Site) error {
+	var b strings.Builder
+
+	b.WriteString("digraph goroutinectx {\n")
+	b.WriteString("\trankdir=LR;\n")
+	b.WriteString("\tnode [shape=box, fontname=\"monospace\"];\n")
+	b.WriteString("\tedge [fontname=\"monospace\"];\n")
+
+	for _, site := range sites {
+		fmt.Fprintf(&b, "\t%s -> %s [label=%s, color=%s];\n",
+			strconv.Quote(site.Func),
+			strconv.Quote(site.Callee),
+			strconv.Quote(edgeLabel(site)),
+			edgeColor(site),
+		)
+	}
+
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// edgeLabel describes the spawn API and how context flows through it.
+func edgeLabel(site Site) string {
+	lines := []string{site.API}
+
+	switch {
+	case site.Context == "":
+		lines = append(lines, "no context in scope")
+	case site.CapturesContext:
+		lines = append(lines, "captures "+site.Context)
+	default:
+		lines = append(lines, "does not capture "+site.Context)
+	}
+
+	if site.CallsDeriver != nil {
+		if *site.CallsDeriver {
+			lines = append(lines, "calls deriver")
+		} else {
+			lines = append(lines, "does not call deriver")
+		}
+	}
+
+	if site.Detached {
+		lines = append(lines, "detached")
+	}
+
+	if site.Ignored {
+		lines = append(lines, "ignored")
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// edgeColor highlights sites that drop an available context or skip the deriver,
+// grays out sites suppressed by an ignore directive, and marks intentionally
+// detached sites blue.
+func edgeColor(site Site) string {
+	if site.Ignored {
+		return "gray"
+	}
+	if site.Detached {
+		return "blue"
+	}
+	if site.Context != "" && !site.CapturesContext {
+		return "red"
+	}
+	if site.CallsDeriver != nil && !*site.CallsDeriver {
+		return "orange"
+	}
+	return "black"
+}
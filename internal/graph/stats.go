@@ -0,0 +1,127 @@
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"text/tabwriter"
+)
+
+// Counts are the adoption metrics of a group of spawn sites.
+type Counts struct {
+	Sites             int `json:"sites"`
+	WithContext       int `json:"with_context"`       // A context is in scope
+	PropagatesContext int `json:"propagates_context"` // A context is in scope and captured
+	DeriverRequired   int `json:"deriver_required"`   // A deriver is configured
+	CallsDeriver      int `json:"calls_deriver"`      // A deriver is configured and called
+	Detached          int `json:"detached"`           // Detached intentionally through a detach function
+	Ignored           int `json:"ignored"`            // Suppressed by an ignore directive
+}
+
+// add counts a site.
+func (c *Counts) add(site Site) {
+	c.Sites++
+
+	if site.Context != "" {
+		c.WithContext++
+		if site.CapturesContext {
+			c.PropagatesContext++
+		}
+	}
+
+	if site.CallsDeriver != nil {
+		c.DeriverRequired++
+		if *site.CallsDeriver {
+			c.CallsDeriver++
+		}
+	}
+
+	if site.Detached {
+		c.Detached++
+	}
+
+	if site.Ignored {
+		c.Ignored++
+	}
+}
+
+// Stats aggregates spawn sites across packages.
+type Stats struct {
+	Packages int               `json:"packages"`
+	Total    Counts            `json:"total"`
+	Checkers map[string]Counts `json:"checkers"` // Keyed by checker name
+	APIs     map[string]Counts `json:"apis"`     // Keyed by spawn API ("go" for go statements)
+}
+
+// NewStats aggregates the sites.
+func NewStats(sites []Site) *Stats {
+	s := &Stats{
+		Checkers: make(map[string]Counts),
+		APIs:     make(map[string]Counts),
+	}
+
+	packages := make(map[string]bool)
+
+	for _, site := range sites {
+		packages[site.Package] = true
+
+		s.Total.add(site)
+
+		checker := s.Checkers[site.Checker]
+		checker.add(site)
+		s.Checkers[site.Checker] = checker
+
+		api := s.APIs[site.API]
+		api.add(site)
+		s.APIs[site.API] = api
+	}
+
+	s.Packages = len(packages)
+
+	return s
+}
+
+// WriteJSON writes the stats as indented JSON.
+func (s *Stats) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(s)
+}
+
+// WriteTable writes the stats as aligned text tables, one per breakdown.
+func (s *Stats) WriteTable(w io.Writer) error {
+	if _, err := fmt.Fprintf(w, "packages: %d\n", s.Packages); err != nil {
+		return err
+	}
+
+	for _, section := range []struct {
+		title string
+		rows  map[string]Counts
+	}{
+		{"CHECKER", s.Checkers},
+		{"API", s.APIs},
+	} {
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+		fmt.Fprintf(tw, "\n%s\tSITES\tWITH CTX\tPROPAGATES\tDERIVER REQ\tCALLS DERIVER\tDETACHED\tIGNORED\n", section.title)
+		for _, name := range slices.Sorted(maps.Keys(section.rows)) {
+			writeRow(tw, name, section.rows[name])
+		}
+		writeRow(tw, "total", s.Total)
+
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeRow writes a single table row.
+func writeRow(w io.Writer, name string, c Counts) {
+	fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%d\t%d\t%d\n",
+		name, c.Sites, c.WithContext, c.PropagatesContext, c.DeriverRequired, c.CallsDeriver, c.Detached, c.Ignored)
+}
@@ -2,6 +2,10 @@ package probe
 
 import (
 	"go/ast"
+	"go/token"
+	"go/types"
+
+	gossa "golang.org/x/tools/go/ssa"
 
 	"github.com/mpyw/goroutinectx/internal/directive/carrier"
 	"github.com/mpyw/goroutinectx/internal/typeutil"
@@ -23,7 +27,44 @@ func (c *Context) FuncLitCapturesContextSSA(lit *ast.FuncLit) (bool, bool) {
 		return false, false
 	}
 
-	return c.Tracer.ClosureCapturesContext(ssaFn, c.Carriers), true
+	return c.ClosureCapturesContext(ssaFn), true
+}
+
+// ClosureCapturesContext checks if an SSA closure captures context, a carrier,
+// or (with ContextFields) a struct whose context field it reads.
+func (c *Context) ClosureCapturesContext(fn *gossa.Function) bool {
+	if c.Tracer.ClosureCapturesContext(fn, c.Carriers) {
+		return true
+	}
+	return c.ContextFields && c.Tracer.ClosureReadsContextField(fn)
+}
+
+// FuncLitIgnoresContextParam uses SSA analysis to check if a func literal declares
+// its own context parameter but ignores it while capturing an outer context.
+// Returns the name of the captured variable, falling back to the context in scope
+// when the context is read from a captured struct field.
+// Returns false if SSA analysis is unavailable (zero false positives).
+func (c *Context) FuncLitIgnoresContextParam(lit *ast.FuncLit) (string, bool) {
+	if c.SSAProg == nil || c.Tracer == nil {
+		return "", false
+	}
+
+	if !c.FuncLitHasContextParam(lit) {
+		return "", false
+	}
+
+	ssaFn := c.SSAProg.FindFuncLit(lit)
+	if ssaFn == nil || !c.Tracer.ClosureIgnoresContextParam(ssaFn) || !c.ClosureCapturesContext(ssaFn) {
+		return "", false
+	}
+
+	if name := c.Tracer.CapturedContextName(ssaFn, c.Carriers); name != "" {
+		return name, true
+	}
+	if len(c.CtxNames) > 0 {
+		return c.CtxNames[0], true
+	}
+	return "ctx", true
 }
 
 // FuncTypeHasContextParam checks if a function type has a context.Context parameter.
@@ -94,6 +135,12 @@ func (c *Context) FuncLitUsesContext(lit *ast.FuncLit) bool {
 	return c.nodeReferencesContext(lit.Body, true)
 }
 
+// BlockUsesContext checks if a block (e.g., a range-over-func loop body) references
+// any context variable. Does NOT descend into nested func literals.
+func (c *Context) BlockUsesContext(body *ast.BlockStmt) bool {
+	return c.nodeReferencesContext(body, true)
+}
+
 // ArgUsesContext checks if an expression references a context variable.
 // Unlike FuncLitUsesContext, this DOES descend into nested func literals.
 func (c *Context) ArgUsesContext(expr ast.Expr) bool {
@@ -111,30 +158,62 @@ func (c *Context) ArgsUseContext(args []ast.Expr) bool {
 }
 
 // nodeReferencesContext checks if a node references any context variable.
+// A carrier whose directive names an accessor is only referenced by calling the
+// accessor (r.Context()) or by passing the carrier itself on (process(r), go process(r)).
 func (c *Context) nodeReferencesContext(node ast.Node, skipNestedFuncLit bool) bool {
+	if expr, ok := node.(ast.Expr); ok && c.carrierWithAccessor(expr) != "" {
+		return true
+	}
+
 	found := false
 	ast.Inspect(node, func(n ast.Node) bool {
 		if found {
 			return false
 		}
-		if skipNestedFuncLit {
-			if _, ok := n.(*ast.FuncLit); ok {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return !skipNestedFuncLit
+		case *ast.CallExpr:
+			if sel, ok := ast.Unparen(n.Fun).(*ast.SelectorExpr); ok && c.carrierWithAccessor(sel.X) == sel.Sel.Name {
+				found = true
+				return false
+			}
+			for _, arg := range n.Args {
+				if c.carrierWithAccessor(arg) != "" {
+					found = true
+					return false
+				}
+			}
+		case *ast.Ident:
+			obj := c.Pass.TypesInfo.ObjectOf(n)
+			if obj == nil {
+				return true
+			}
+			if typeutil.IsContextType(obj.Type()) ||
+				(carrier.IsCarrierType(obj.Type(), c.Carriers) && carrier.AccessorOf(obj.Type(), c.Carriers) == "") {
+				found = true
 				return false
 			}
-		}
-		ident, ok := n.(*ast.Ident)
-		if !ok {
-			return true
-		}
-		obj := c.Pass.TypesInfo.ObjectOf(ident)
-		if obj == nil {
-			return true
-		}
-		if typeutil.IsContextType(obj.Type()) || carrier.IsCarrierType(obj.Type(), c.Carriers) {
-			found = true
-			return false
 		}
 		return true
 	})
 	return found
 }
+
+// carrierWithAccessor returns the accessor of the carrier variable an expression
+// names (e.g., r or &r), or "" if it names no carrier with an accessor.
+func (c *Context) carrierWithAccessor(expr ast.Expr) string {
+	expr = ast.Unparen(expr)
+	if unary, ok := expr.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+		expr = ast.Unparen(unary.X)
+	}
+	ident, ok := expr.(*ast.Ident)
+	if !ok {
+		return ""
+	}
+	obj, ok := c.Pass.TypesInfo.ObjectOf(ident).(*types.Var)
+	if !ok {
+		return ""
+	}
+	return carrier.AccessorOf(obj.Type(), c.Carriers)
+}
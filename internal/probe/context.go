@@ -8,16 +8,19 @@ import (
 	"golang.org/x/tools/go/analysis"
 
 	"github.com/mpyw/goroutinectx/internal/directive/carrier"
+	"github.com/mpyw/goroutinectx/internal/funcspec"
 	"github.com/mpyw/goroutinectx/internal/ssa"
 )
 
 // Context provides context for pattern checking.
 type Context struct {
-	Pass     *analysis.Pass
-	Tracer   *ssa.Tracer
-	SSAProg  *ssa.Program
-	CtxNames []string
-	Carriers []carrier.Carrier
+	Pass          *analysis.Pass
+	Tracer        *ssa.Tracer
+	SSAProg       *ssa.Program
+	CtxNames      []string
+	Carriers      []carrier.Carrier
+	Detachers     []funcspec.Spec
+	ContextFields bool // Context-typed fields of captured structs count as context
 }
 
 // VarOf extracts *types.Var from an identifier.
@@ -0,0 +1,78 @@
+package probe
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"slices"
+
+	"github.com/mpyw/goroutinectx/internal/funcspec"
+)
+
+// WithoutCancel is the standard library function detaching a context from the
+// cancellation of its parent.
+var WithoutCancel = funcspec.Spec{PkgPath: "context", FuncName: "WithoutCancel"}
+
+// WithWithoutCancel returns the detach functions with context.WithoutCancel added,
+// for checkers that suggest it in their messages and so always accept it.
+func WithWithoutCancel(detachers []funcspec.Spec) []funcspec.Spec {
+	if slices.Contains(detachers, WithoutCancel) {
+		return detachers
+	}
+	return append([]funcspec.Spec{WithoutCancel}, detachers...)
+}
+
+// IsDetachFunc checks if fn is one of the detach functions.
+func IsDetachFunc(fn *types.Func, detachers []funcspec.Spec) bool {
+	return slices.ContainsFunc(detachers, func(spec funcspec.Spec) bool {
+		return spec.Matches(fn)
+	})
+}
+
+// FuncLitUsesDetachedContext checks if a func literal calls one of the configured
+// detach functions, or uses a value returned by one of them. Such closures detach
+// from the request lifetime intentionally.
+func (c *Context) FuncLitUsesDetachedContext(lit *ast.FuncLit) bool {
+	if len(c.Detachers) == 0 {
+		return false
+	}
+
+	// Try SSA-based check first
+	if c.SSAProg != nil && c.Tracer != nil {
+		if ssaFn := c.SSAProg.FindFuncLit(lit); ssaFn != nil {
+			return c.Tracer.ClosureUsesDetachedContext(ssaFn, c.Detachers)
+		}
+	}
+
+	// Fall back to AST-based check
+	found := false
+	ast.Inspect(lit.Body, func(n ast.Node) bool {
+		if found {
+			return false
+		}
+
+		switch node := n.(type) {
+		case *ast.CallExpr:
+			if c.IsDetachCall(node) {
+				found = true
+			}
+		case *ast.Ident:
+			v := c.VarOf(node)
+			if v == nil || lit.Pos() <= v.Pos() && v.Pos() < lit.End() {
+				return true // Not captured
+			}
+			if call := c.CallExprAssignedTo(v, token.NoPos); call != nil && c.IsDetachCall(call) {
+				found = true
+			}
+		}
+		return true
+	})
+
+	return found
+}
+
+// IsDetachCall checks if a call invokes one of the configured detach functions.
+func (c *Context) IsDetachCall(call *ast.CallExpr) bool {
+	fn := funcspec.ExtractFunc(c.Pass, call)
+	return fn != nil && IsDetachFunc(fn, c.Detachers)
+}
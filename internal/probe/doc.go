@@ -9,11 +9,13 @@
 // # Context Structure
 //
 //	type Context struct {
-//	    Pass     *analysis.Pass       // The analysis pass
-//	    Tracer   *ssa.Tracer          // SSA-based value tracer
-//	    SSAProg  *ssa.Program         // SSA program representation
-//	    CtxNames []string             // Context variable names in scope
-//	    Carriers []carrier.Carrier    // Configured carrier types
+//	    Pass          *analysis.Pass       // The analysis pass
+//	    Tracer        *ssa.Tracer          // SSA-based value tracer
+//	    SSAProg       *ssa.Program         // SSA program representation
+//	    CtxNames      []string             // Context variable names in scope
+//	    Carriers      []carrier.Carrier    // Configured carrier types
+//	    Detachers     []funcspec.Spec      // Configured detach functions
+//	    ContextFields bool                 // Context-typed struct fields count as context
 //	}
 //
 // # Analysis Methods
@@ -28,6 +30,7 @@
 //	│ Factory Functions    │ FactoryCallReturnsContextUsingFunc           │
 //	│ Variable Resolution  │ FuncLitOfIdent                               │
 //	│ SSA Analysis         │ FuncLitCapturesContextSSA                    │
+//	│ Intentional Detach   │ FuncLitUsesDetachedContext                   │
 //	└──────────────────────┴──────────────────────────────────────────────┘
 //
 // # SSA vs AST Analysis
@@ -9,7 +9,11 @@ import (
 
 	"github.com/mpyw/goroutinectx/internal/directive/carrier"
 	"github.com/mpyw/goroutinectx/internal/directive/ignore"
+	"github.com/mpyw/goroutinectx/internal/directive/spawner"
+	"github.com/mpyw/goroutinectx/internal/funcspec"
+	"github.com/mpyw/goroutinectx/internal/gitdiff"
 	"github.com/mpyw/goroutinectx/internal/probe"
+	"github.com/mpyw/goroutinectx/internal/registry"
 	"github.com/mpyw/goroutinectx/internal/scope"
 	"github.com/mpyw/goroutinectx/internal/ssa"
 )
@@ -18,37 +22,55 @@ import (
 type Runner struct {
 	goStmtCheckers []GoStmtChecker
 	callCheckers   []CallChecker
+	rangeCheckers  []RangeStmtChecker
 	ssaProg        *ssa.Program
 	tracer         *ssa.Tracer
 	carriers       []carrier.Carrier
+	detachers      []funcspec.Spec
+	contextFields  bool
+	spawns         scope.Spawns
 	ignoreMaps     map[string]ignore.Map
 	skipFiles      map[string]bool
+	changes        *gitdiff.Changes
 }
 
 // NewRunner creates a new runner.
 func NewRunner(
 	goStmtCheckers []GoStmtChecker,
 	callCheckers []CallChecker,
+	rangeCheckers []RangeStmtChecker,
 	ssaProg *ssa.Program,
 	carriers []carrier.Carrier,
+	detachers []funcspec.Spec,
+	contextFields bool,
+	spawners *spawner.Map,
 	ignoreMaps map[string]ignore.Map,
 	skipFiles map[string]bool,
+	changes *gitdiff.Changes,
 ) *Runner {
+	reg := registry.New()
+	RegisterSpawnAPIs(reg)
+
 	return &Runner{
 		goStmtCheckers: goStmtCheckers,
 		callCheckers:   callCheckers,
+		rangeCheckers:  rangeCheckers,
 		ssaProg:        ssaProg,
 		tracer:         ssa.NewTracer(),
 		carriers:       carriers,
+		detachers:      detachers,
+		contextFields:  contextFields,
+		spawns:         scope.Spawns{APIs: reg, Spawners: spawners},
 		ignoreMaps:     ignoreMaps,
 		skipFiles:      skipFiles,
+		changes:        changes,
 	}
 }
 
 // Run executes all checkers on the pass.
 func (r *Runner) Run(pass *analysis.Pass, insp *inspector.Inspector) {
 	// Build context scopes for functions with context parameters
-	funcScopes := scope.Build(pass, insp, r.carriers)
+	funcScopes := scope.Build(pass, insp, r.carriers, r.contextFields, r.spawns)
 
 	// Node types we're interested in
 	nodeFilter := []ast.Node{
@@ -56,6 +78,7 @@ func (r *Runner) Run(pass *analysis.Pass, insp *inspector.Inspector) {
 		(*ast.FuncLit)(nil),
 		(*ast.GoStmt)(nil),
 		(*ast.CallExpr)(nil),
+		(*ast.RangeStmt)(nil),
 	}
 
 	// Check nodes within context-aware functions
@@ -75,11 +98,13 @@ func (r *Runner) Run(pass *analysis.Pass, insp *inspector.Inspector) {
 		}
 
 		cctx := &probe.Context{
-			Pass:     pass,
-			Tracer:   r.tracer,
-			SSAProg:  r.ssaProg,
-			CtxNames: s.CtxNames,
-			Carriers: r.carriers,
+			Pass:          pass,
+			Tracer:        r.tracer,
+			SSAProg:       r.ssaProg,
+			CtxNames:      s.CtxNames,
+			Carriers:      r.carriers,
+			Detachers:     r.detachers,
+			ContextFields: r.contextFields,
 		}
 
 		switch node := n.(type) {
@@ -87,6 +112,8 @@ func (r *Runner) Run(pass *analysis.Pass, insp *inspector.Inspector) {
 			r.checkGoStmt(cctx, node)
 		case *ast.CallExpr:
 			r.checkCallExpr(cctx, node)
+		case *ast.RangeStmt:
+			r.checkRangeStmt(cctx, node)
 		}
 
 		return true
@@ -96,9 +123,7 @@ func (r *Runner) Run(pass *analysis.Pass, insp *inspector.Inspector) {
 // checkGoStmt runs all GoStmt checkers.
 func (r *Runner) checkGoStmt(cctx *probe.Context, stmt *ast.GoStmt) {
 	for _, checker := range r.goStmtCheckers {
-		if r.shouldIgnore(cctx.Pass, stmt.Pos(), checker.Name()) {
-			continue
-		}
+		cctx := r.withIgnore(cctx, stmt.Pos(), checker.Name())
 
 		result := checker.CheckGoStmt(cctx, stmt)
 		if result.OK {
@@ -111,7 +136,7 @@ func (r *Runner) checkGoStmt(cctx *probe.Context, stmt *ast.GoStmt) {
 		}
 
 		if msg != "" {
-			cctx.Pass.Reportf(stmt.Pos(), "%s", msg)
+			cctx.Pass.Reportf(reportPos(result, stmt.Pos()), "%s", msg)
 		}
 	}
 }
@@ -123,22 +148,48 @@ func (r *Runner) checkCallExpr(cctx *probe.Context, call *ast.CallExpr) {
 			continue
 		}
 
-		if r.shouldIgnore(cctx.Pass, call.Pos(), checker.Name()) {
+		cctx := r.withIgnore(cctx, call.Pos(), checker.Name())
+
+		result := checker.CheckCall(cctx, call)
+		if result.OK {
 			continue
 		}
 
-		result := checker.CheckCall(cctx, call)
+		if result.Message != "" {
+			cctx.Pass.Reportf(reportPos(result, getCallReportPos(call)), "%s", result.Message)
+		}
+	}
+}
+
+// checkRangeStmt runs all RangeStmt checkers.
+func (r *Runner) checkRangeStmt(cctx *probe.Context, stmt *ast.RangeStmt) {
+	for _, checker := range r.rangeCheckers {
+		if !checker.MatchRange(cctx.Pass, stmt) {
+			continue
+		}
+
+		cctx := r.withIgnore(cctx, stmt.Pos(), checker.Name())
+
+		result := checker.CheckRangeStmt(cctx, stmt)
 		if result.OK {
 			continue
 		}
 
 		if result.Message != "" {
-			reportPos := getCallReportPos(call)
-			cctx.Pass.Reportf(reportPos, "%s", result.Message)
+			cctx.Pass.Reportf(reportPos(result, stmt.Pos()), "%s", result.Message)
 		}
 	}
 }
 
+// reportPos returns the position a failing result is reported at,
+// falling back to the checked node's position.
+func reportPos(result *Result, fallback token.Pos) token.Pos {
+	if result.Pos.IsValid() {
+		return result.Pos
+	}
+	return fallback
+}
+
 // getCallReportPos returns the best position to report for a call expression.
 func getCallReportPos(call *ast.CallExpr) token.Pos {
 	if sel, ok := call.Fun.(*ast.SelectorExpr); ok {
@@ -147,6 +198,35 @@ func getCallReportPos(call *ast.CallExpr) token.Pos {
 	return call.Pos()
 }
 
+// withIgnore returns a copy of cctx whose diagnostics are categorized under the
+// checker name and dropped when an ignore directive covering anchor, or the reported
+// position itself, applies to it,
+// or when they fall outside the lines changed since -new-from-rev. Checkers that report several
+// findings themselves are filtered the same way as returned results, and ignore
+// directives are only marked as used when they actually suppress a diagnostic.
+func (r *Runner) withIgnore(cctx *probe.Context, anchor token.Pos, checkerName ignore.CheckerName) *probe.Context {
+	pass := *cctx.Pass
+	pass.Report = func(d analysis.Diagnostic) {
+		// Findings reported inside the checked node (e.g., at a context use in
+		// the closure) can also be ignored at their own line
+		if r.shouldIgnore(cctx.Pass, anchor, checkerName) || (d.Pos != anchor && r.shouldIgnore(cctx.Pass, d.Pos, checkerName)) {
+			return
+		}
+		position := cctx.Pass.Fset.Position(d.Pos)
+		if !r.changes.Contains(position.Filename, position.Line) {
+			return // Not a new finding under -new-from-rev
+		}
+		if d.Category == "" {
+			d.Category = string(checkerName)
+		}
+		cctx.Pass.Report(d)
+	}
+
+	filtered := *cctx
+	filtered.Pass = &pass
+	return &filtered
+}
+
 // shouldIgnore checks if the position should be ignored for the given checker.
 func (r *Runner) shouldIgnore(pass *analysis.Pass, pos token.Pos, checkerName ignore.CheckerName) bool {
 	filename := pass.Fset.Position(pos).Filename
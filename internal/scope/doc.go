@@ -29,7 +29,7 @@
 //
 // Use [Build] to create a scope map for all functions in a package:
 //
-//	funcScopes := scope.Build(pass, inspector, carriers)
+//	funcScopes := scope.Build(pass, inspector, carriers, fields, scope.Spawns{APIs: reg, Spawners: spawners})
 //
 // The resulting [Map] maps AST nodes (FuncDecl, FuncLit) to their [Scope]:
 //
@@ -73,4 +73,31 @@
 //	        // inner has its own scope with ["innerCtx"]
 //	    }
 //	}
+//
+// Spawned func literals without their own context parameters (go statements,
+// callback arguments of the spawn APIs in [Spawns] such as errgroup.Group.Go, and
+// arguments of spawners) have a scope inheriting the enclosing context names,
+// extended with the context variables they capture. Callbacks of other calls,
+// such as sort.Slice, run synchronously and do not.
+// Nested spawns are checked even when the outermost function has no context
+// parameter:
+//
+//	func main() {
+//	    ctx := context.Background()
+//	    go func() {
+//	        // scope with ["ctx"], captured
+//	        go func() { ... }()
+//	    }()
+//	}
+//
+// # Receiver Fields
+//
+// If fields is true, a method whose receiver struct has context.Context fields
+// has context scope, named by field path:
+//
+//	type job struct{ ctx context.Context }
+//
+//	func (j *job) run() {
+//	    // ctx available: ["j.ctx"]
+//	}
 package scope
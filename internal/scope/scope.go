@@ -3,11 +3,16 @@ package scope
 
 import (
 	"go/ast"
+	"go/types"
+	"slices"
 
 	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/ast/inspector"
 
 	"github.com/mpyw/goroutinectx/internal/directive/carrier"
+	"github.com/mpyw/goroutinectx/internal/directive/spawner"
+	"github.com/mpyw/goroutinectx/internal/funcspec"
+	"github.com/mpyw/goroutinectx/internal/registry"
 	"github.com/mpyw/goroutinectx/internal/typeutil"
 )
 
@@ -19,30 +24,152 @@ type Scope struct {
 // Map maps AST nodes to their scopes.
 type Map map[ast.Node]*Scope
 
+// Spawns identifies the calls running their func literal arguments on new goroutines.
+type Spawns struct {
+	APIs     *registry.Registry // Spawn APIs (e.g., errgroup.Group.Go)
+	Spawners *spawner.Map       // //goroutinectx:spawner and -external-spawner functions
+}
+
 // Build identifies functions with context parameters.
-func Build(pass *analysis.Pass, insp *inspector.Inspector, carriers []carrier.Carrier) Map {
+// If fields is true, methods whose receiver struct has context.Context fields
+// also have context scope, named by field path (e.g., "j.ctx").
+// Spawned func literals without context parameters inherit the enclosing scope,
+// extended with the context variables they capture (see [capturedContexts]).
+// Func literals are spawned by go statements and the calls identified by spawns;
+// callbacks of other calls (e.g., sort.Slice) run synchronously and inherit nothing.
+func Build(pass *analysis.Pass, insp *inspector.Inspector, carriers []carrier.Carrier, fields bool, spawns Spawns) Map {
 	m := make(Map)
 
-	insp.Preorder([]ast.Node{(*ast.FuncDecl)(nil), (*ast.FuncLit)(nil)}, func(n ast.Node) {
+	insp.WithStack([]ast.Node{(*ast.FuncDecl)(nil), (*ast.FuncLit)(nil)}, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+
 		var fnType *ast.FuncType
+		var recv *ast.FieldList
 
 		switch fn := n.(type) {
 		case *ast.FuncDecl:
 			fnType = fn.Type
+			if fields {
+				recv = fn.Recv
+			}
 		case *ast.FuncLit:
 			fnType = fn.Type
 		}
 
-		if scope := findScope(pass, fnType, carriers); scope != nil {
+		if scope := findScope(pass, fnType, recv, carriers); scope != nil {
 			m[n] = scope
+			return true
 		}
+
+		if lit, ok := n.(*ast.FuncLit); ok && spawns.isSpawnedFuncLit(pass, lit, stack) {
+			if scope := inheritScope(pass, lit, FindEnclosing(m, stack[:len(stack)-1]), carriers); scope != nil {
+				m[n] = scope
+			}
+		}
+
+		return true
 	})
 
 	return m
 }
 
-// findScope checks if the function has context parameters.
-func findScope(pass *analysis.Pass, fnType *ast.FuncType, carriers []carrier.Carrier) *Scope {
+// isSpawnedFuncLit checks if a func literal is spawned by a go statement, passed
+// as a callback argument to a spawn API (e.g., errgroup.Group.Go), or passed to a spawner.
+func (s Spawns) isSpawnedFuncLit(pass *analysis.Pass, lit *ast.FuncLit, stack []ast.Node) bool {
+	if len(stack) < 2 {
+		return false
+	}
+
+	call, ok := stack[len(stack)-2].(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+
+	if call.Fun == lit {
+		if len(stack) < 3 {
+			return false
+		}
+		_, isGo := stack[len(stack)-3].(*ast.GoStmt)
+		return isGo
+	}
+
+	idx := slices.Index(call.Args, ast.Expr(lit))
+	if idx < 0 {
+		return false
+	}
+
+	fn := funcspec.ExtractFunc(pass, call)
+	if fn == nil {
+		return false
+	}
+
+	if s.APIs != nil {
+		if match := s.APIs.MatchFunc(fn); match != nil {
+			return !match.AlwaysSpawns && idx >= match.CallbackArgIdx
+		}
+	}
+
+	return s.Spawners.IsSpawner(fn)
+}
+
+// inheritScope returns the scope of a spawned func literal without context
+// parameters: the enclosing context names followed by the captured ones.
+// Returns nil if there is neither.
+func inheritScope(pass *analysis.Pass, lit *ast.FuncLit, outer *Scope, carriers []carrier.Carrier) *Scope {
+	var ctxNames []string
+	if outer != nil {
+		ctxNames = append(ctxNames, outer.CtxNames...)
+	}
+
+	for _, name := range capturedContexts(pass, lit, carriers) {
+		if !slices.Contains(ctxNames, name) {
+			ctxNames = append(ctxNames, name)
+		}
+	}
+
+	if len(ctxNames) == 0 {
+		return nil
+	}
+
+	return &Scope{CtxNames: ctxNames}
+}
+
+// capturedContexts returns the names of the local context variables (or carriers)
+// declared outside the func literal and referenced inside it, including by nested
+// func literals, in order of first reference.
+func capturedContexts(pass *analysis.Pass, lit *ast.FuncLit, carriers []carrier.Carrier) []string {
+	var names []string
+
+	ast.Inspect(lit.Body, func(n ast.Node) bool {
+		ident, ok := n.(*ast.Ident)
+		if !ok {
+			return true
+		}
+
+		v, ok := pass.TypesInfo.Uses[ident].(*types.Var)
+		if !ok || v.IsField() || v.Parent() == nil || v.Parent() == v.Pkg().Scope() {
+			return true
+		}
+		if lit.Pos() <= v.Pos() && v.Pos() < lit.End() {
+			return true // Declared inside the func literal
+		}
+		if !typeutil.IsContextType(v.Type()) && !carrier.IsCarrierType(v.Type(), carriers) {
+			return true
+		}
+
+		if !slices.Contains(names, v.Name()) {
+			names = append(names, v.Name())
+		}
+		return true
+	})
+
+	return names
+}
+
+// findScope checks if the function has context parameters or receiver fields.
+func findScope(pass *analysis.Pass, fnType *ast.FuncType, recv *ast.FieldList, carriers []carrier.Carrier) *Scope {
 	if fnType == nil || fnType.Params == nil {
 		return nil
 	}
@@ -62,6 +189,8 @@ func findScope(pass *analysis.Pass, fnType *ast.FuncType, carriers []carrier.Car
 		}
 	}
 
+	ctxNames = append(ctxNames, receiverFields(pass, recv)...)
+
 	if len(ctxNames) == 0 {
 		return nil
 	}
@@ -69,6 +198,41 @@ func findScope(pass *analysis.Pass, fnType *ast.FuncType, carriers []carrier.Car
 	return &Scope{CtxNames: ctxNames}
 }
 
+// receiverFields returns the field paths of the context.Context fields
+// of a named receiver struct (e.g., "j.ctx").
+func receiverFields(pass *analysis.Pass, recv *ast.FieldList) []string {
+	if recv == nil || len(recv.List) == 0 || len(recv.List[0].Names) == 0 {
+		return nil
+	}
+
+	name := recv.List[0].Names[0].Name
+	if name == "_" {
+		return nil
+	}
+
+	typ := pass.TypesInfo.TypeOf(recv.List[0].Type)
+	if typ == nil {
+		return nil
+	}
+	if ptr, ok := typ.Underlying().(*types.Pointer); ok {
+		typ = ptr.Elem()
+	}
+
+	st, ok := typ.Underlying().(*types.Struct)
+	if !ok {
+		return nil
+	}
+
+	var paths []string
+	for field := range st.Fields() {
+		if typeutil.IsContextType(field.Type()) {
+			paths = append(paths, name+"."+field.Name())
+		}
+	}
+
+	return paths
+}
+
 // FindEnclosing finds the closest enclosing function with a context parameter.
 func FindEnclosing(scopes Map, stack []ast.Node) *Scope {
 	for i := len(stack) - 1; i >= 0; i-- {
@@ -2,6 +2,7 @@ package ssa
 
 import (
 	"go/ast"
+	"go/types"
 
 	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/analysis/passes/buildssa"
@@ -32,6 +33,51 @@ func Build(pass *analysis.Pass) *Program {
 	}
 }
 
+// BuildPackage creates an SSA program from the analysis pass without the
+// buildssa analyzer, for analyzers that run on dependencies and only need
+// the SSA form of some packages. The source functions are the package-level
+// functions and methods.
+func BuildPackage(pass *analysis.Pass) *Program {
+	prog := ssa.NewProgram(pass.Fset, 0)
+
+	created := make(map[*types.Package]bool)
+	var createAll func([]*types.Package)
+	createAll = func(pkgs []*types.Package) {
+		for _, p := range pkgs {
+			if !created[p] {
+				created[p] = true
+				prog.CreatePackage(p, nil, nil, true)
+				createAll(p.Imports())
+			}
+		}
+	}
+	createAll(pass.Pkg.Imports())
+
+	pkg := prog.CreatePackage(pass.Pkg, pass.Files, pass.TypesInfo, false)
+	pkg.Build()
+
+	var srcFuncs []*ssa.Function
+	for _, file := range pass.Files {
+		for _, decl := range file.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok {
+				continue
+			}
+			if obj, ok := pass.TypesInfo.Defs[funcDecl.Name].(*types.Func); ok {
+				if fn := prog.FuncValue(obj); fn != nil {
+					srcFuncs = append(srcFuncs, fn)
+				}
+			}
+		}
+	}
+
+	return &Program{
+		Program:  prog,
+		Pkg:      pkg,
+		SrcFuncs: srcFuncs,
+	}
+}
+
 // FuncAt returns the SSA function containing the given position.
 func (p *Program) FuncAt(pos ast.Node) *ssa.Function {
 	for _, fn := range p.SrcFuncs {
@@ -72,6 +118,30 @@ func (p *Program) findFuncLitInFunc(fn *ssa.Function, lit *ast.FuncLit) *ssa.Fun
 	return nil
 }
 
+// FindRangeFunc finds the synthetic SSA yield function holding the loop body
+// of a given range-over-func RangeStmt AST node.
+func (p *Program) FindRangeFunc(stmt *ast.RangeStmt) *ssa.Function {
+	if p == nil || stmt == nil {
+		return nil
+	}
+
+	fns := []*ssa.Function{p.FuncAt(stmt)}
+	if fns[0] == nil {
+		return nil
+	}
+
+	for i := 0; i < len(fns); i++ {
+		for _, anon := range fns[i].AnonFuncs {
+			if anon.Syntax() == stmt {
+				return anon
+			}
+			fns = append(fns, anon)
+		}
+	}
+
+	return nil
+}
+
 // FindFuncDecl finds the SSA function for a given FuncDecl AST node.
 func (p *Program) FindFuncDecl(decl *ast.FuncDecl) *ssa.Function {
 	if p == nil || decl == nil {
@@ -89,3 +159,73 @@ func (p *Program) FindFuncDecl(decl *ast.FuncDecl) *ssa.Function {
 	}
 	return nil
 }
+
+// FindGoStmt finds the SSA go instruction for a given GoStmt AST node.
+func (p *Program) FindGoStmt(stmt *ast.GoStmt) *ssa.Go {
+	if p == nil || stmt == nil {
+		return nil
+	}
+
+	fns := []*ssa.Function{p.FuncAt(stmt)}
+	if fns[0] == nil {
+		return nil
+	}
+
+	for i := 0; i < len(fns); i++ {
+		fns = append(fns, fns[i].AnonFuncs...)
+
+		for _, block := range fns[i].Blocks {
+			for _, instr := range block.Instrs {
+				if g, ok := instr.(*ssa.Go); ok && g.Pos() == stmt.Go {
+					return g
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// FindCall finds the SSA call instruction for a given CallExpr AST node.
+func (p *Program) FindCall(call *ast.CallExpr) *ssa.Call {
+	if p == nil || call == nil {
+		return nil
+	}
+
+	fns := []*ssa.Function{p.FuncAt(call)}
+	if fns[0] == nil {
+		return nil
+	}
+
+	for i := 0; i < len(fns); i++ {
+		fns = append(fns, fns[i].AnonFuncs...)
+
+		for _, block := range fns[i].Blocks {
+			for _, instr := range block.Instrs {
+				if c, ok := instr.(*ssa.Call); ok && c.Pos() == call.Lparen {
+					return c
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// MakeClosureOf finds the MakeClosure instruction creating the given closure.
+// Returns nil for closures without free variables.
+func MakeClosureOf(closure *ssa.Function) *ssa.MakeClosure {
+	if closure == nil || closure.Parent() == nil {
+		return nil
+	}
+
+	for _, block := range closure.Parent().Blocks {
+		for _, instr := range block.Instrs {
+			if mc, ok := instr.(*ssa.MakeClosure); ok && mc.Fn == closure {
+				return mc
+			}
+		}
+	}
+
+	return nil
+}
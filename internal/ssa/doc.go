@@ -81,6 +81,15 @@
 //  2. Tracks whether calls are in defer statements
 //  3. Checks if deriver functions are called at start vs only in defer
 //
+// [Tracer.FirstOriginalContextUse] then checks ordering with SSA dominators:
+// each use of a captured context must be dominated by a deriver call, and the
+// original context must not be used after one.
+// [Tracer.FirstDiscardedDeriverCall] reports a deriver call whose returned
+// context is never used (-goroutine-deriver-used).
+//
+// If [deriver.Matcher.Inherited] is set (-goroutine-deriver-once), a closure
+// capturing a context derived in an enclosing spawned closure also passes.
+//
 // This enables warnings like:
 //
 //	go func() {
@@ -88,6 +97,21 @@
 //	    doWork(ctx)
 //	}()
 //
+// # Wrapper Inference
+//
+// [DerivedContext] traces the context a function returns back to its context
+// parameter through calls, returning the functions called on every return
+// path. The facts analyzer records them for deriver wrappers, building the
+// package with [BuildPackage], since it also runs on dependencies.
+//
+// # Loop Detection
+//
+// [Tracer.DeferredLoopCancel] traces the values reaching a spawned goroutine back
+// to a context.WithCancel-style call. The call and the spawn are in the same loop
+// when their blocks reach each other in the control flow graph; the result's
+// cancel is then followed through variables and phi nodes to see whether it is
+// only deferred.
+//
 // # Helper Functions
 //
 // The package exports helper functions for SSA analysis:
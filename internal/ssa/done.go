@@ -0,0 +1,260 @@
+package ssa
+
+import (
+	"go/token"
+
+	"golang.org/x/tools/go/ssa"
+
+	"github.com/mpyw/goroutinectx/internal/funcspec"
+	"github.com/mpyw/goroutinectx/internal/typeutil"
+)
+
+// DoneContextPass is a call passing a done context downstream.
+type DoneContextPass struct {
+	Name string    // Context variable passed
+	Pos  token.Pos // Position of the call
+}
+
+// FirstDonePass returns the first call in the callback, or in closures nested in
+// it, passing the context the callback is registered on (the first argument of
+// register, e.g., context.AfterFunc) or a context derived from it as an argument.
+// Contexts derived through a detach function, calls preparing a detach call and
+// method calls on the context itself (e.g., ctx.Err()) do not pass it.
+// Assignments to captured variables that are overwritten before the callback is
+// registered are not observed by the callback and are skipped.
+// Returns nil if the callback never passes the done context.
+func (t *Tracer) FirstDonePass(register *ssa.Call, callback *ssa.Function, detachers []funcspec.Spec) *DoneContextPass {
+	if register == nil || callback == nil || len(register.Call.Args) == 0 {
+		return nil
+	}
+
+	d := &doneTracer{
+		register:  register,
+		detachers: detachers,
+		visited:   make(map[ssa.Value]bool),
+	}
+	d.done = d.reaching(register.Call.Args[0])
+
+	var first *DoneContextPass
+
+	fns := []*ssa.Function{callback}
+	for i := 0; i < len(fns); i++ {
+		fns = append(fns, fns[i].AnonFuncs...)
+
+		for _, block := range fns[i].Blocks {
+			for _, instr := range block.Instrs {
+				call, ok := instr.(ssa.CallInstruction)
+				if !ok || isDetachCall(call.Common(), detachers) || d.preparesDetach(call) {
+					continue
+				}
+				if first != nil && call.Pos() >= first.Pos {
+					continue
+				}
+				for _, arg := range call.Common().Args {
+					if name, ok := d.derived(arg); ok {
+						first = &DoneContextPass{Name: name, Pos: call.Pos()}
+						break
+					}
+				}
+			}
+		}
+	}
+
+	return first
+}
+
+// doneTracer traces values back to the done context of a callback registration.
+type doneTracer struct {
+	register  *ssa.Call
+	done      ssa.Value
+	detachers []funcspec.Spec
+	visited   map[ssa.Value]bool
+}
+
+// derived checks if a value is the done context or a context derived from it,
+// returning the name of the variable it is passed through.
+func (d *doneTracer) derived(v ssa.Value) (string, bool) {
+	if v == d.done {
+		return valueName(v), true
+	}
+	if d.visited[v] {
+		return "", false
+	}
+	d.visited[v] = true
+	defer delete(d.visited, v)
+
+	switch v := v.(type) {
+	case *ssa.FreeVar:
+		binding := freeVarBinding(v)
+		if binding == nil {
+			return "", false
+		}
+		if _, ok := d.derived(binding); ok {
+			return v.Name(), true
+		}
+
+	case *ssa.Alloc:
+		for _, val := range d.observedStores(v) {
+			if name, ok := d.derived(val); ok {
+				return name, true
+			}
+		}
+
+	case *ssa.UnOp:
+		if v.Op != token.MUL {
+			return "", false
+		}
+		if store := reachingStore(v); store != nil {
+			return d.derived(store.Val)
+		}
+		if name, ok := d.derived(v.X); ok {
+			if fv, isFreeVar := v.X.(*ssa.FreeVar); isFreeVar {
+				return fv.Name(), true
+			}
+			return name, true
+		}
+
+	case *ssa.Call:
+		if !returnsContext(v) || isDetachCall(&v.Call, d.detachers) {
+			return "", false
+		}
+		for _, arg := range v.Call.Args {
+			if name, ok := d.derived(arg); ok {
+				return name, true
+			}
+		}
+
+	case *ssa.Extract:
+		return d.derived(v.Tuple)
+
+	case *ssa.Phi:
+		for _, edge := range v.Edges {
+			if name, ok := d.derived(edge); ok {
+				return name, true
+			}
+		}
+
+	case *ssa.ChangeType:
+		return d.derived(v.X)
+
+	case *ssa.ChangeInterface:
+		return d.derived(v.X)
+
+	case *ssa.MakeInterface:
+		return d.derived(v.X)
+
+	case *ssa.TypeAssert:
+		return d.derived(v.X)
+	}
+
+	return "", false
+}
+
+// observedStores returns the values stored to a captured variable that a callback
+// may observe: every store except those overwritten by another store before the
+// callback is registered.
+func (d *doneTracer) observedStores(alloc *ssa.Alloc) []ssa.Value {
+	var stores []*ssa.Store
+	for _, ref := range *alloc.Referrers() {
+		if store, ok := ref.(*ssa.Store); ok && store.Addr == alloc {
+			stores = append(stores, store)
+		}
+	}
+
+	var values []ssa.Value
+	for _, store := range stores {
+		if !d.overwritten(store, stores) {
+			values = append(values, store.Val)
+		}
+	}
+	return values
+}
+
+// overwritten checks if another store follows the store on every path to the registration.
+func (d *doneTracer) overwritten(store *ssa.Store, stores []*ssa.Store) bool {
+	if store.Parent() != d.register.Parent() {
+		return false
+	}
+	for _, other := range stores {
+		if other != store && dominates(store, other) && dominates(other, d.register) {
+			return true
+		}
+	}
+	return false
+}
+
+// reaching resolves a load of a local variable to the value stored last before it.
+func (d *doneTracer) reaching(v ssa.Value) ssa.Value {
+	for {
+		load, ok := v.(*ssa.UnOp)
+		if !ok || load.Op != token.MUL {
+			return v
+		}
+		store := reachingStore(load)
+		if store == nil {
+			return v
+		}
+		v = store.Val
+	}
+}
+
+// preparesDetach checks if the context a call returns flows into a detach call
+// (e.g., context.WithoutCancel(context.WithValue(ctx, k, v))).
+func (d *doneTracer) preparesDetach(call ssa.CallInstruction) bool {
+	value := call.Value()
+	if value == nil || !returnsContext(value) {
+		return false
+	}
+
+	for _, ref := range *value.Referrers() {
+		if extract, ok := ref.(*ssa.Extract); ok && typeutil.IsContextType(extract.Type()) {
+			for _, extractRef := range *extract.Referrers() {
+				if next, ok := extractRef.(ssa.CallInstruction); ok && isDetachCall(next.Common(), d.detachers) {
+					return true
+				}
+			}
+		}
+		if next, ok := ref.(ssa.CallInstruction); ok && isDetachCall(next.Common(), d.detachers) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// reachingStore returns the store to the loaded local variable that precedes the
+// load on every path and is not followed by another such store, or nil.
+func reachingStore(load *ssa.UnOp) *ssa.Store {
+	refs := load.X.Referrers()
+	if refs == nil {
+		return nil
+	}
+
+	var last *ssa.Store
+	for _, ref := range *refs {
+		store, ok := ref.(*ssa.Store)
+		if !ok || store.Addr != load.X || store.Parent() != load.Parent() || !dominates(store, load) {
+			continue
+		}
+		if last == nil || dominates(last, store) {
+			last = store
+		}
+	}
+
+	return last
+}
+
+// valueName returns the variable name of a value, if it has one.
+func valueName(v ssa.Value) string {
+	switch v := v.(type) {
+	case *ssa.Parameter:
+		return v.Name()
+	case *ssa.FreeVar:
+		return v.Name()
+	case *ssa.UnOp:
+		return valueName(v.X)
+	case *ssa.Alloc:
+		return v.Comment
+	}
+	return ""
+}
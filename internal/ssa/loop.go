@@ -0,0 +1,238 @@
+package ssa
+
+import (
+	"go/types"
+
+	"golang.org/x/tools/go/ssa"
+
+	"github.com/mpyw/goroutinectx/internal/funcspec"
+)
+
+// cancelDerivers derive a context together with a cancel function.
+var cancelDerivers = []funcspec.Spec{
+	{PkgPath: "context", FuncName: "WithCancel"},
+	{PkgPath: "context", FuncName: "WithCancelCause"},
+	{PkgPath: "context", FuncName: "WithTimeout"},
+	{PkgPath: "context", FuncName: "WithTimeoutCause"},
+	{PkgPath: "context", FuncName: "WithDeadline"},
+	{PkgPath: "context", FuncName: "WithDeadlineCause"},
+}
+
+// DeferredLoopCancel checks the values reaching a goroutine spawned by the spawn
+// instruction (a MakeClosure or Go) for a context derived in the same loop of the
+// enclosing function, whose cancel is only deferred until that function returns.
+// Returns the index of the first such value and the deriving function, or (-1, nil).
+func (t *Tracer) DeferredLoopCancel(spawn ssa.Instruction, values []ssa.Value) (int, *types.Func) {
+	if spawn == nil || spawn.Block() == nil {
+		return -1, nil
+	}
+
+	for i, v := range values {
+		call := t.derivingCall(v, make(map[ssa.Value]bool))
+		if call == nil || call.Parent() != spawn.Parent() {
+			continue
+		}
+		if !inSameLoop(call.Block(), spawn.Block()) {
+			continue
+		}
+		cancel := extractAt(call, 1)
+		if cancel == nil {
+			continue
+		}
+		if deferred, called := cancelUses(cancel); deferred && !called {
+			return i, ExtractCalledFunc(&call.Call)
+		}
+	}
+
+	return -1, nil
+}
+
+// derivingCall traces a value back to a cancel-returning context deriver call.
+// Captured variables and phi nodes are traced through any of their assignments.
+func (t *Tracer) derivingCall(v ssa.Value, visited map[ssa.Value]bool) *ssa.Call {
+	if v == nil || visited[v] {
+		return nil
+	}
+	visited[v] = true
+
+	switch v := v.(type) {
+	case *ssa.Call:
+		if isCancelDeriver(&v.Call) {
+			return v
+		}
+
+	case *ssa.Extract:
+		if v.Index == 0 {
+			return t.derivingCall(v.Tuple, visited)
+		}
+
+	case *ssa.Phi:
+		for _, edge := range v.Edges {
+			if call := t.derivingCall(edge, visited); call != nil {
+				return call
+			}
+		}
+
+	case *ssa.Alloc:
+		for _, ref := range *v.Referrers() {
+			store, ok := ref.(*ssa.Store)
+			if !ok || store.Addr != v {
+				continue
+			}
+			if call := t.derivingCall(store.Val, visited); call != nil {
+				return call
+			}
+		}
+
+	case *ssa.UnOp:
+		return t.derivingCall(v.X, visited)
+
+	case *ssa.FreeVar:
+		return t.derivingCall(freeVarBinding(v), visited)
+	}
+
+	return nil
+}
+
+// isCancelDeriver checks if a call derives a context with a cancel function.
+func isCancelDeriver(call *ssa.CallCommon) bool {
+	fn := ExtractCalledFunc(call)
+	if fn == nil {
+		return false
+	}
+	for _, spec := range cancelDerivers {
+		if spec.Matches(fn) {
+			return true
+		}
+	}
+	return false
+}
+
+// extractAt returns the Extract of the call result at index, or nil if unused.
+func extractAt(call *ssa.Call, index int) *ssa.Extract {
+	for _, ref := range *call.Referrers() {
+		if extract, ok := ref.(*ssa.Extract); ok && extract.Index == index {
+			return extract
+		}
+	}
+	return nil
+}
+
+// cancelUses follows a cancel function through variables and phi nodes, reporting
+// whether a defer in its function calls it, and whether anything else calls it,
+// receives it as an argument, or captures it in a closure that is not deferred.
+func cancelUses(cancel ssa.Value) (deferred, called bool) {
+	visited := make(map[ssa.Value]bool)
+	work := []ssa.Value{cancel}
+
+	for len(work) > 0 {
+		v := work[len(work)-1]
+		work = work[:len(work)-1]
+		if visited[v] {
+			continue
+		}
+		visited[v] = true
+
+		refs := v.Referrers()
+		if refs == nil {
+			continue
+		}
+
+		for _, ref := range *refs {
+			switch instr := ref.(type) {
+			case *ssa.Defer:
+				if usesValue(&instr.Call, v) {
+					deferred = true
+				}
+
+			case ssa.CallInstruction:
+				if usesValue(instr.Common(), v) {
+					called = true
+				}
+
+			case *ssa.MakeClosure:
+				if isDeferredClosure(instr) {
+					deferred = true
+				} else {
+					called = true
+				}
+
+			case *ssa.Store:
+				if instr.Val == v {
+					work = append(work, instr.Addr)
+				}
+
+			case *ssa.UnOp:
+				work = append(work, instr)
+
+			case *ssa.Phi:
+				work = append(work, instr)
+
+			case *ssa.ChangeType:
+				work = append(work, instr)
+
+			case *ssa.MakeInterface:
+				called = true // Escapes into an interface value
+			}
+		}
+	}
+
+	return deferred, called
+}
+
+// usesValue checks if a call invokes v or passes it as an argument.
+func usesValue(call *ssa.CallCommon, v ssa.Value) bool {
+	if call.Value == v {
+		return true
+	}
+	for _, arg := range call.Args {
+		if arg == v {
+			return true
+		}
+	}
+	return false
+}
+
+// isDeferredClosure checks if a closure is only invoked by defer statements.
+func isDeferredClosure(mc *ssa.MakeClosure) bool {
+	refs := mc.Referrers()
+	if refs == nil || len(*refs) == 0 {
+		return false
+	}
+	for _, ref := range *refs {
+		d, ok := ref.(*ssa.Defer)
+		if !ok || d.Call.Value != mc {
+			return false
+		}
+	}
+	return true
+}
+
+// inSameLoop checks if both blocks lie on a common cycle of the control flow graph.
+func inSameLoop(a, b *ssa.BasicBlock) bool {
+	if a == b {
+		return reaches(a.Succs, a)
+	}
+	return reaches([]*ssa.BasicBlock{a}, b) && reaches([]*ssa.BasicBlock{b}, a)
+}
+
+// reaches checks if the target block is reachable from any of the start blocks.
+func reaches(start []*ssa.BasicBlock, target *ssa.BasicBlock) bool {
+	work := append([]*ssa.BasicBlock(nil), start...)
+	visited := make(map[*ssa.BasicBlock]bool)
+
+	for len(work) > 0 {
+		block := work[len(work)-1]
+		work = work[:len(work)-1]
+		if block == target {
+			return true
+		}
+		if visited[block] {
+			continue
+		}
+		visited[block] = true
+		work = append(work, block.Succs...)
+	}
+
+	return false
+}
@@ -0,0 +1,272 @@
+package ssa
+
+import (
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/ssa"
+
+	"github.com/mpyw/goroutinectx/internal/deriver"
+	"github.com/mpyw/goroutinectx/internal/typeutil"
+)
+
+// ContextUse is a use of a captured context in a closure that calls a deriver.
+type ContextUse struct {
+	Name  string    // Captured context variable
+	Pos   token.Pos // Position of the use
+	After bool      // The use is dominated by a deriver call (the original is used instead of the derived one)
+}
+
+// FirstOriginalContextUse returns the first use of a captured context (or of a
+// context parameter, as in gotask callbacks) in the closure that is not dominated
+// by a deriver call, or, failing that, the first use of the original context
+// after one. Uses that prepare a deriver call
+// (e.g., newrelic.FromContext(ctx) for Transaction.NewGoroutine) are not counted.
+// Returns nil if every use is derived, or if the closure calls no deriver itself.
+func (t *Tracer) FirstOriginalContextUse(closure *ssa.Function, matcher *deriver.Matcher) *ContextUse {
+	if closure == nil || matcher == nil || matcher.IsEmpty() {
+		return nil
+	}
+
+	derivers := deriverCallsIn(closure, matcher)
+	if len(derivers) == 0 {
+		return nil
+	}
+
+	var before, after *ContextUse
+
+	for _, block := range closure.Blocks {
+		for _, instr := range block.Instrs {
+			// go and defer statements use their operands as well (go f(ctx))
+			call, ok := instr.(ssa.CallInstruction)
+			if !ok || ExtractIIFE(call.Common()) != nil {
+				continue
+			}
+			if value := call.Value(); value != nil && derivers[value] {
+				continue
+			}
+
+			fv := originalContextOperand(call.Common())
+			if fv == nil {
+				continue
+			}
+			if value := call.Value(); value != nil && preparesDeriver(value, derivers, make(map[ssa.Value]bool)) {
+				continue
+			}
+
+			use := &ContextUse{Name: fv.Name(), Pos: call.Pos(), After: dominatedByAny(call, derivers)}
+			if use.After {
+				if after == nil || use.Pos < after.Pos {
+					after = use
+				}
+			} else if before == nil || use.Pos < before.Pos {
+				before = use
+			}
+		}
+	}
+
+	if before != nil {
+		return before
+	}
+	return after
+}
+
+// deriverCallsIn returns the non-deferred deriver calls made directly in fn.
+func deriverCallsIn(fn *ssa.Function, matcher *deriver.Matcher) map[*ssa.Call]bool {
+	calls := make(map[*ssa.Call]bool)
+
+	for _, block := range fn.Blocks {
+		for _, instr := range block.Instrs {
+			call, ok := instr.(*ssa.Call)
+			if !ok {
+				continue
+			}
+			if calledFn := ExtractCalledFunc(&call.Call); calledFn != nil && matcher.MatchesFunc(calledFn) {
+				calls[call] = true
+			}
+		}
+	}
+
+	return calls
+}
+
+// originalContextOperand returns the captured context free variable or context
+// parameter passed to the call (as an argument or interface receiver), as long
+// as the closure has not reassigned it before the load.
+func originalContextOperand(call *ssa.CallCommon) ssa.Value {
+	operands := call.Args
+	if call.IsInvoke() {
+		operands = append([]ssa.Value{call.Value}, operands...)
+	}
+
+	for _, op := range operands {
+		if fv := originalContext(op); fv != nil {
+			return fv
+		}
+	}
+
+	return nil
+}
+
+// originalContext returns the captured context free variable a value loads or
+// the context parameter it is, or nil if the value is neither or a reassignment
+// in the closure dominates it. A reassigned parameter is a different SSA value.
+func originalContext(v ssa.Value) ssa.Value {
+	switch v := v.(type) {
+	case *ssa.FreeVar, *ssa.Parameter:
+		if typeutil.IsContextType(v.Type()) {
+			return v
+		}
+
+	case *ssa.UnOp:
+		fv, ok := v.X.(*ssa.FreeVar)
+		if !ok || !typeutil.IsContextType(fv.Type()) {
+			return nil
+		}
+		for _, ref := range *fv.Referrers() {
+			if store, ok := ref.(*ssa.Store); ok && store.Addr == fv && dominates(store, v) {
+				return nil // ctx = deriver(ctx) reassigned the captured variable
+			}
+		}
+		return fv
+	}
+
+	return nil
+}
+
+// preparesDeriver checks if the result of a value flows into a deriver call.
+func preparesDeriver(v ssa.Value, derivers map[*ssa.Call]bool, visited map[ssa.Value]bool) bool {
+	if visited[v] {
+		return false
+	}
+	visited[v] = true
+
+	refs := v.Referrers()
+	if refs == nil {
+		return false
+	}
+
+	for _, ref := range *refs {
+		if call, ok := ref.(*ssa.Call); ok && derivers[call] {
+			return true
+		}
+		if next, ok := ref.(ssa.Value); ok && preparesDeriver(next, derivers, visited) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// dominatedByAny checks if any of the deriver calls dominates the instruction.
+func dominatedByAny(instr ssa.Instruction, derivers map[*ssa.Call]bool) bool {
+	for call := range derivers {
+		if dominates(call, instr) {
+			return true
+		}
+	}
+	return false
+}
+
+// dominates checks if instruction a runs before b on every path reaching b.
+func dominates(a, b ssa.Instruction) bool {
+	if a.Block() != b.Block() {
+		return a.Block().Dominates(b.Block())
+	}
+
+	for _, instr := range a.Block().Instrs {
+		switch instr {
+		case a:
+			return true
+		case b:
+			return false
+		}
+	}
+
+	return false
+}
+
+// FirstDiscardedDeriverCall returns the first deriver call in the closure whose
+// derived context is never used: it does not flow into a call, a return, a
+// nested closure or a store that replaces the captured context variable.
+// Deriver calls that return no context (e.g., Transaction.NewGoroutine) are skipped.
+// Returns nil if every derived context is used.
+func (t *Tracer) FirstDiscardedDeriverCall(closure *ssa.Function, matcher *deriver.Matcher) *ssa.Call {
+	if closure == nil || matcher == nil || matcher.IsEmpty() {
+		return nil
+	}
+
+	var first *ssa.Call
+
+	for call := range deriverCallsIn(closure, matcher) {
+		if !returnsContext(call) || contextUsed(call, make(map[ssa.Value]bool)) {
+			continue
+		}
+		if first == nil || call.Pos() < first.Pos() {
+			first = call
+		}
+	}
+
+	return first
+}
+
+// returnsContext checks if a call returns a context.Context, alone or in a tuple.
+func returnsContext(call *ssa.Call) bool {
+	if typeutil.IsContextType(call.Type()) {
+		return true
+	}
+	tuple, ok := call.Type().(*types.Tuple)
+	if !ok {
+		return false
+	}
+	for v := range tuple.Variables() {
+		if typeutil.IsContextType(v.Type()) {
+			return true
+		}
+	}
+	return false
+}
+
+// contextUsed follows a derived context through variables, phi nodes and
+// conversions, checking if anything consumes it.
+func contextUsed(v ssa.Value, visited map[ssa.Value]bool) bool {
+	if visited[v] {
+		return false
+	}
+	visited[v] = true
+
+	refs := v.Referrers()
+	if refs == nil {
+		return false
+	}
+
+	for _, ref := range *refs {
+		switch instr := ref.(type) {
+		case ssa.CallInstruction, *ssa.Return, *ssa.MakeClosure, *ssa.Send, *ssa.MapUpdate:
+			return true
+
+		case *ssa.Store:
+			if instr.Val != v {
+				continue
+			}
+			if _, ok := instr.Addr.(*ssa.Alloc); !ok {
+				return true // Replaces a captured variable, or escapes into a field
+			}
+			if contextUsed(instr.Addr, visited) {
+				return true
+			}
+
+		case *ssa.Extract:
+			if typeutil.IsContextType(instr.Type()) && contextUsed(instr, visited) {
+				return true
+			}
+
+		case ssa.Value:
+			if contextUsed(instr, visited) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
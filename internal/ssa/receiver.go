@@ -0,0 +1,49 @@
+package ssa
+
+import (
+	"go/token"
+
+	"golang.org/x/tools/go/ssa"
+
+	"github.com/mpyw/goroutinectx/internal/funcspec"
+)
+
+// ReceiverCalledBefore checks if a method matching spec is called on the receiver
+// of the method call before it on every path, in the same function
+// (e.g., errgroup.Group.SetLimit before errgroup.Group.Go).
+func (t *Tracer) ReceiverCalledBefore(call *ssa.Call, spec funcspec.Spec) bool {
+	if call == nil || call.Parent() == nil || len(call.Call.Args) == 0 {
+		return false
+	}
+
+	recv := receiverRoot(call.Call.Args[0])
+
+	for _, block := range call.Parent().Blocks {
+		for _, instr := range block.Instrs {
+			other, ok := instr.(*ssa.Call)
+			if !ok || other == call || len(other.Call.Args) == 0 {
+				continue
+			}
+			fn := ExtractCalledFunc(&other.Call)
+			if fn == nil || !spec.Matches(fn) {
+				continue
+			}
+			if receiverRoot(other.Call.Args[0]) == recv && dominates(other, call) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// receiverRoot returns the variable a receiver is loaded from, or the receiver itself.
+func receiverRoot(v ssa.Value) ssa.Value {
+	for {
+		unop, ok := v.(*ssa.UnOp)
+		if !ok || unop.Op != token.MUL {
+			return v
+		}
+		v = unop.X
+	}
+}
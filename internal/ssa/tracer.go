@@ -1,7 +1,9 @@
 package ssa
 
 import (
+	"go/token"
 	"go/types"
+	"slices"
 
 	"golang.org/x/tools/go/ssa"
 
@@ -22,19 +24,177 @@ func NewTracer() *Tracer {
 // ClosureCapturesContext checks if a closure captures any context.Context variable
 // or a configured carrier type.
 func (t *Tracer) ClosureCapturesContext(closure *ssa.Function, carriers []carrier.Carrier) bool {
+	return t.CapturedContextName(closure, carriers) != ""
+}
+
+// CapturedContextName returns the name of the first context.Context or carrier
+// variable a closure captures, or "" if it captures none.
+// A carrier whose directive names an accessor only counts if the closure, or a
+// closure nested in it, calls the accessor on it or passes it to a call.
+func (t *Tracer) CapturedContextName(closure *ssa.Function, carriers []carrier.Carrier) string {
 	if closure == nil {
-		return false
+		return ""
 	}
 
 	for _, fv := range closure.FreeVars {
-		if typeutil.IsContextType(fv.Type()) || carrier.IsCarrierType(fv.Type(), carriers) {
-			return true
+		if typeutil.IsContextType(fv.Type()) {
+			return fv.Name()
+		}
+		if !carrier.IsCarrierType(fv.Type(), carriers) {
+			continue
+		}
+		if accessor := carrier.AccessorOf(fv.Type(), carriers); accessor == "" || carrierPropagated(closure, fv, accessor) {
+			return fv.Name()
+		}
+	}
+
+	return ""
+}
+
+// carrierPropagated checks if a closure, or a closure nested in it, calls the
+// accessor on a captured carrier or passes the carrier itself to a call.
+func carrierPropagated(closure *ssa.Function, fv *ssa.FreeVar, accessor string) bool {
+	fns := []*ssa.Function{closure}
+	for i := 0; i < len(fns); i++ {
+		fns = append(fns, fns[i].AnonFuncs...)
+
+		for _, block := range fns[i].Blocks {
+			for _, instr := range block.Instrs {
+				call, ok := instr.(ssa.CallInstruction)
+				if !ok {
+					continue
+				}
+				common := call.Common()
+
+				args := common.Args
+				if common.IsInvoke() {
+					if common.Method.Name() == accessor && rootedAt(common.Value, fv) {
+						return true
+					}
+				} else if fn := ExtractCalledFunc(common); fn != nil && fn.Type().(*types.Signature).Recv() != nil && len(args) > 0 {
+					if fn.Name() == accessor && rootedAt(args[0], fv) {
+						return true
+					}
+					args = args[1:]
+				}
+
+				if slices.ContainsFunc(args, func(arg ssa.Value) bool { return rootedAt(arg, fv) }) {
+					return true
+				}
+			}
 		}
 	}
 
 	return false
 }
 
+// rootedAt checks if a value is the captured variable itself, loaded, converted
+// to an interface or captured again by a nested closure.
+func rootedAt(v ssa.Value, fv *ssa.FreeVar) bool {
+	for {
+		switch x := v.(type) {
+		case *ssa.FreeVar:
+			if x == fv {
+				return true
+			}
+			v = freeVarBinding(x)
+			if v == nil {
+				return false
+			}
+		case *ssa.UnOp:
+			if x.Op != token.MUL {
+				return false
+			}
+			v = x.X
+		case *ssa.MakeInterface:
+			v = x.X
+		case *ssa.ChangeInterface:
+			v = x.X
+		case *ssa.ChangeType:
+			v = x.X
+		default:
+			return false
+		}
+	}
+}
+
+// ClosureReadsContextField checks if a closure, or a closure nested in it, reads a
+// context.Context field from a captured struct or pointer (e.g., j.ctx for a captured j).
+func (t *Tracer) ClosureReadsContextField(closure *ssa.Function) bool {
+	if closure == nil {
+		return false
+	}
+
+	fns := []*ssa.Function{closure}
+	for i := 0; i < len(fns); i++ {
+		fns = append(fns, fns[i].AnonFuncs...)
+
+		for _, block := range fns[i].Blocks {
+			for _, instr := range block.Instrs {
+				switch v := instr.(type) {
+				case *ssa.FieldAddr:
+					if ptr, ok := v.Type().(*types.Pointer); ok && typeutil.IsContextType(ptr.Elem()) && isCapturedPath(v.X, closure) {
+						return true
+					}
+				case *ssa.Field:
+					if typeutil.IsContextType(v.Type()) && isCapturedPath(v.X, closure) {
+						return true
+					}
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// isCapturedPath checks if a field selection base is rooted at a free variable
+// of closure, following free variables of nested closures up to it.
+func isCapturedPath(v ssa.Value, closure *ssa.Function) bool {
+	for {
+		switch x := v.(type) {
+		case *ssa.FreeVar:
+			if x.Parent() == closure {
+				return true
+			}
+			binding := freeVarBinding(x)
+			if binding == nil {
+				return false
+			}
+			v = binding
+		case *ssa.UnOp:
+			v = x.X
+		case *ssa.FieldAddr:
+			v = x.X
+		case *ssa.Field:
+			v = x.X
+		default:
+			return false
+		}
+	}
+}
+
+// ClosureIgnoresContextParam checks if a closure declares a context.Context parameter
+// but never references it (blank, unnamed, or simply unused).
+func (t *Tracer) ClosureIgnoresContextParam(closure *ssa.Function) bool {
+	if closure == nil {
+		return false
+	}
+
+	hasCtxParam := false
+	for _, param := range closure.Params {
+		if !typeutil.IsContextType(param.Type()) {
+			continue
+		}
+		hasCtxParam = true
+		if refs := param.Referrers(); refs != nil && len(*refs) > 0 {
+			return false
+		}
+	}
+
+	return hasCtxParam
+}
+
 // DeriverResult represents the result of deriver function detection.
 type DeriverResult struct {
 	FoundAtStart     bool
@@ -51,14 +211,19 @@ func (t *Tracer) ClosureCallsDeriver(closure *ssa.Function, matcher *deriver.Mat
 
 	// Check if any OR group is satisfied at start
 	for _, andGroup := range matcher.OrGroups {
-		if t.checkAndGroup(calls, andGroup, false) {
+		if t.checkAndGroup(matcher, calls, andGroup, false) {
 			return DeriverResult{FoundAtStart: true}
 		}
 	}
 
+	// Accept a context derived by an enclosing spawned closure
+	if matcher.Inherited && t.capturesInheritedContext(closure, matcher) {
+		return DeriverResult{FoundAtStart: true}
+	}
+
 	// Check if deriver is only in defer
 	for _, andGroup := range matcher.OrGroups {
-		if t.checkAndGroup(calls, andGroup, true) {
+		if t.checkAndGroup(matcher, calls, andGroup, true) {
 			return DeriverResult{FoundOnlyInDefer: true}
 		}
 	}
@@ -66,6 +231,101 @@ func (t *Tracer) ClosureCallsDeriver(closure *ssa.Function, matcher *deriver.Mat
 	return DeriverResult{}
 }
 
+// capturesInheritedContext checks if a closure captures a context derived by a
+// deriver call in another spawned closure enclosing it.
+func (t *Tracer) capturesInheritedContext(closure *ssa.Function, matcher *deriver.Matcher) bool {
+	for _, fv := range closure.FreeVars {
+		if !typeutil.IsContextType(fv.Type()) {
+			continue
+		}
+		if t.isInheritedValue(fv, closure, matcher, make(map[ssa.Value]bool)) {
+			return true
+		}
+	}
+	return false
+}
+
+// isInheritedValue traces a value back to a deriver call made in a spawned closure
+// other than closure. Captured variables and phi nodes are inherited only if every
+// assignment is.
+func (t *Tracer) isInheritedValue(v ssa.Value, closure *ssa.Function, matcher *deriver.Matcher, visited map[ssa.Value]bool) bool {
+	if visited[v] {
+		return false
+	}
+	visited[v] = true
+
+	switch v := v.(type) {
+	case *ssa.Call:
+		fn := ExtractCalledFunc(&v.Call)
+		return fn != nil && matcher.MatchesFunc(fn) && v.Parent() != closure && isSpawnedClosure(v.Parent())
+
+	case *ssa.Extract:
+		return t.isInheritedValue(v.Tuple, closure, matcher, visited)
+
+	case *ssa.Phi:
+		for _, edge := range v.Edges {
+			if !t.isInheritedValue(edge, closure, matcher, visited) {
+				return false
+			}
+		}
+		return len(v.Edges) > 0
+
+	case *ssa.Alloc:
+		stored := false
+		for _, ref := range *v.Referrers() {
+			store, ok := ref.(*ssa.Store)
+			if !ok || store.Addr != v {
+				continue
+			}
+			if !t.isInheritedValue(store.Val, closure, matcher, visited) {
+				return false
+			}
+			stored = true
+		}
+		return stored
+
+	case *ssa.UnOp:
+		return t.isInheritedValue(v.X, closure, matcher, visited)
+
+	case *ssa.FreeVar:
+		if binding := freeVarBinding(v); binding != nil {
+			return t.isInheritedValue(binding, closure, matcher, visited)
+		}
+	}
+
+	return false
+}
+
+// isSpawnedClosure checks if an anonymous function is run by a go statement,
+// or passed as an argument (e.g., to errgroup.Group.Go) by its parent.
+func isSpawnedClosure(fn *ssa.Function) bool {
+	if fn == nil || fn.Parent() == nil {
+		return false
+	}
+
+	var value ssa.Value = fn
+	if mc := MakeClosureOf(fn); mc != nil {
+		value = mc
+	}
+
+	for _, block := range fn.Parent().Blocks {
+		for _, instr := range block.Instrs {
+			call, ok := instr.(ssa.CallInstruction)
+			if !ok {
+				continue
+			}
+			if _, isGo := instr.(*ssa.Go); isGo && call.Common().Value == value {
+				return true
+			}
+			if slices.Contains(call.Common().Args, value) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 type deriverCall struct {
 	fn      *types.Func
 	inDefer bool
@@ -104,14 +364,14 @@ func (t *Tracer) collectDeriverCalls(fn *ssa.Function, inDefer bool, visited map
 	return calls
 }
 
-func (t *Tracer) checkAndGroup(calls []deriverCall, andGroup []funcspec.Spec, includeDefer bool) bool {
+func (t *Tracer) checkAndGroup(matcher *deriver.Matcher, calls []deriverCall, andGroup []funcspec.Spec, includeDefer bool) bool {
 	for _, spec := range andGroup {
 		found := false
 		for _, call := range calls {
 			if !includeDefer && call.inDefer {
 				continue
 			}
-			if call.fn != nil && spec.Matches(call.fn) {
+			if call.fn != nil && matcher.Satisfies(spec, call.fn) {
 				found = true
 				break
 			}
@@ -123,19 +383,141 @@ func (t *Tracer) checkAndGroup(calls []deriverCall, andGroup []funcspec.Spec, in
 	return true
 }
 
+// ClosureUsesDetachedContext checks if a closure calls any of the detach functions,
+// or captures a value returned by one of them.
+func (t *Tracer) ClosureUsesDetachedContext(closure *ssa.Function, detachers []funcspec.Spec) bool {
+	if closure == nil || len(detachers) == 0 {
+		return false
+	}
+
+	for _, block := range closure.Blocks {
+		for _, instr := range block.Instrs {
+			call, ok := instr.(ssa.CallInstruction)
+			if !ok {
+				continue
+			}
+			if isDetachCall(call.Common(), detachers) {
+				return true
+			}
+		}
+	}
+
+	for _, fv := range closure.FreeVars {
+		if t.isDetachedValue(fv, detachers, make(map[ssa.Value]bool)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isDetachedValue traces a value back to a detach function call.
+// Captured variables and phi nodes are detached only if every assignment is.
+func (t *Tracer) isDetachedValue(v ssa.Value, detachers []funcspec.Spec, visited map[ssa.Value]bool) bool {
+	if visited[v] {
+		return false
+	}
+	visited[v] = true
+
+	switch v := v.(type) {
+	case *ssa.Call:
+		return isDetachCall(&v.Call, detachers)
+
+	case *ssa.Extract:
+		return t.isDetachedValue(v.Tuple, detachers, visited)
+
+	case *ssa.Phi:
+		for _, edge := range v.Edges {
+			if !t.isDetachedValue(edge, detachers, visited) {
+				return false
+			}
+		}
+		return len(v.Edges) > 0
+
+	case *ssa.Alloc:
+		stored := false
+		for _, ref := range *v.Referrers() {
+			store, ok := ref.(*ssa.Store)
+			if !ok || store.Addr != v {
+				continue
+			}
+			if !t.isDetachedValue(store.Val, detachers, visited) {
+				return false
+			}
+			stored = true
+		}
+		return stored
+
+	case *ssa.UnOp:
+		return t.isDetachedValue(v.X, detachers, visited)
+
+	case *ssa.FreeVar:
+		if binding := freeVarBinding(v); binding != nil {
+			return t.isDetachedValue(binding, detachers, visited)
+		}
+	}
+
+	return false
+}
+
+// freeVarBinding returns the value bound to a free variable by the enclosing MakeClosure.
+func freeVarBinding(fv *ssa.FreeVar) ssa.Value {
+	fn := fv.Parent()
+	if fn == nil || fn.Parent() == nil {
+		return nil
+	}
+
+	idx := -1
+	for i, v := range fn.FreeVars {
+		if v == fv {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return nil
+	}
+
+	for _, block := range fn.Parent().Blocks {
+		for _, instr := range block.Instrs {
+			mc, ok := instr.(*ssa.MakeClosure)
+			if ok && mc.Fn == fn && idx < len(mc.Bindings) {
+				return mc.Bindings[idx]
+			}
+		}
+	}
+
+	return nil
+}
+
+// isDetachCall checks if a call invokes one of the detach functions.
+func isDetachCall(call *ssa.CallCommon, detachers []funcspec.Spec) bool {
+	fn := ExtractCalledFunc(call)
+	if fn == nil {
+		return false
+	}
+	for _, spec := range detachers {
+		if spec.Matches(fn) {
+			return true
+		}
+	}
+	return false
+}
+
 // =============================================================================
 // Helper Functions
 // =============================================================================
 
 // ExtractCalledFunc extracts the types.Func from a CallCommon.
+// Instantiated generic functions and methods are returned as their origin.
 func ExtractCalledFunc(call *ssa.CallCommon) *types.Func {
 	if call.IsInvoke() {
-		return call.Method
+		return call.Method.Origin()
 	}
 
 	if fn := call.StaticCallee(); fn != nil {
 		if obj, ok := fn.Object().(*types.Func); ok {
-			return obj
+			return obj.Origin()
 		}
 		if origin := fn.Origin(); origin != nil {
 			if obj, ok := origin.Object().(*types.Func); ok {
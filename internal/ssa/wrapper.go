@@ -0,0 +1,185 @@
+package ssa
+
+import (
+	"go/types"
+	"slices"
+
+	"golang.org/x/tools/go/ssa"
+
+	"github.com/mpyw/goroutinectx/internal/funcspec"
+	"github.com/mpyw/goroutinectx/internal/typeutil"
+)
+
+// DerivedContext returns the functions fn calls to derive the context it returns
+// from its context parameter, common to every return. Calls to known wrappers
+// contribute the functions they call themselves.
+// Returns nil if fn has no context parameter or result, or if any return passes
+// a context that is not derived from the parameter by a call.
+func DerivedContext(fn *ssa.Function, wrappers map[*types.Func][]funcspec.Spec) []funcspec.Spec {
+	if fn == nil || !slices.ContainsFunc(fn.Params, func(p *ssa.Parameter) bool {
+		return typeutil.IsContextType(p.Type())
+	}) {
+		return nil
+	}
+
+	idx := contextResultIndex(fn.Signature)
+	if idx < 0 {
+		return nil
+	}
+
+	var derives []funcspec.Spec
+	returned := false
+
+	for _, block := range fn.Blocks {
+		if len(block.Instrs) == 0 {
+			continue
+		}
+		ret, ok := block.Instrs[len(block.Instrs)-1].(*ssa.Return)
+		if !ok {
+			continue
+		}
+
+		specs, ok := derivation(ret.Results[idx], wrappers, make(map[ssa.Value]bool))
+		if !ok || len(specs) == 0 {
+			return nil
+		}
+
+		if returned {
+			derives = intersectSpecs(derives, specs)
+		} else {
+			derives, returned = specs, true
+		}
+	}
+
+	if len(derives) == 0 {
+		return nil
+	}
+	return derives
+}
+
+// contextResultIndex returns the index of the first context.Context result, or -1.
+func contextResultIndex(sig *types.Signature) int {
+	for i := range sig.Results().Len() {
+		if typeutil.IsContextType(sig.Results().At(i).Type()) {
+			return i
+		}
+	}
+	return -1
+}
+
+// derivation traces a value back to a context parameter through calls, returning
+// the functions called on the way. Every operand reaching the parameter contributes
+// its calls, and phi nodes and variables contribute the calls common to every
+// assignment. The second result reports whether the value reaches the parameter.
+func derivation(v ssa.Value, wrappers map[*types.Func][]funcspec.Spec, onStack map[ssa.Value]bool) ([]funcspec.Spec, bool) {
+	if onStack[v] {
+		return nil, false
+	}
+	onStack[v] = true
+	defer delete(onStack, v)
+
+	switch v := v.(type) {
+	case *ssa.Parameter:
+		return nil, typeutil.IsContextType(v.Type())
+
+	case *ssa.Call:
+		operands := v.Call.Args
+		if v.Call.IsInvoke() {
+			operands = append([]ssa.Value{v.Call.Value}, operands...)
+		}
+
+		var specs []funcspec.Spec
+		reached := false
+		for _, op := range operands {
+			if s, ok := derivation(op, wrappers, onStack); ok {
+				specs = unionSpecs(specs, s)
+				reached = true
+			}
+		}
+		if !reached {
+			return nil, false
+		}
+
+		if fn := ExtractCalledFunc(&v.Call); fn != nil {
+			specs = unionSpecs(specs, []funcspec.Spec{funcspec.Of(fn)})
+			specs = unionSpecs(specs, wrappers[fn])
+		}
+		return specs, true
+
+	case *ssa.Phi:
+		return commonDerivation(v.Edges, wrappers, onStack)
+
+	case *ssa.Alloc:
+		var stored []ssa.Value
+		for _, ref := range *v.Referrers() {
+			if store, ok := ref.(*ssa.Store); ok && store.Addr == v {
+				stored = append(stored, store.Val)
+			}
+		}
+		return commonDerivation(stored, wrappers, onStack)
+
+	case *ssa.Extract:
+		return derivation(v.Tuple, wrappers, onStack)
+
+	case *ssa.UnOp:
+		return derivation(v.X, wrappers, onStack)
+
+	case *ssa.ChangeType:
+		return derivation(v.X, wrappers, onStack)
+
+	case *ssa.ChangeInterface:
+		return derivation(v.X, wrappers, onStack)
+
+	case *ssa.MakeInterface:
+		return derivation(v.X, wrappers, onStack)
+
+	case *ssa.TypeAssert:
+		return derivation(v.X, wrappers, onStack)
+	}
+
+	return nil, false
+}
+
+// commonDerivation returns the calls common to the derivations of all values,
+// which must all reach the context parameter.
+func commonDerivation(values []ssa.Value, wrappers map[*types.Func][]funcspec.Spec, onStack map[ssa.Value]bool) ([]funcspec.Spec, bool) {
+	if len(values) == 0 {
+		return nil, false
+	}
+
+	var common []funcspec.Spec
+	for i, value := range values {
+		specs, ok := derivation(value, wrappers, onStack)
+		if !ok {
+			return nil, false
+		}
+		if i == 0 {
+			common = specs
+		} else {
+			common = intersectSpecs(common, specs)
+		}
+	}
+
+	return common, true
+}
+
+// unionSpecs appends the specs of b missing from a.
+func unionSpecs(a, b []funcspec.Spec) []funcspec.Spec {
+	for _, spec := range b {
+		if !slices.Contains(a, spec) {
+			a = append(a, spec)
+		}
+	}
+	return a
+}
+
+// intersectSpecs returns the specs of a also in b.
+func intersectSpecs(a, b []funcspec.Spec) []funcspec.Spec {
+	var common []funcspec.Spec
+	for _, spec := range a {
+		if slices.Contains(b, spec) {
+			common = append(common, spec)
+		}
+	}
+	return common
+}
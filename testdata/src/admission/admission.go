@@ -0,0 +1,288 @@
+// Package admission contains test fixtures for the admission checker.
+// Blocking spawn admission (semaphores, limited errgroups) must respect context cancellation.
+package admission
+
+import (
+	"context"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/semaphore"
+)
+
+// ===== semaphore.Weighted.Acquire - SHOULD REPORT =====
+
+// [BAD]: Semaphore acquired with Background
+//
+// Acquire ignores the scope context, so admission cannot be cancelled.
+func badAcquireBackground(ctx context.Context) {
+	sem := semaphore.NewWeighted(2)
+	_ = sem.Acquire(context.Background(), 1) // want `semaphore.Weighted.Acquire\(\) should use context "ctx" so spawn admission respects cancellation \(or use TryAcquire\)`
+	go func() {
+		defer sem.Release(1)
+		_ = ctx
+	}()
+}
+
+// [BAD]: Semaphore acquired with TODO
+//
+// Acquire with context.TODO ignores the scope context.
+func badAcquireTODO(ctx context.Context) {
+	sem := semaphore.NewWeighted(2)
+	if err := sem.Acquire(context.TODO(), 1); err != nil { // want `semaphore.Weighted.Acquire\(\) should use context "ctx" so spawn admission respects cancellation \(or use TryAcquire\)`
+		return
+	}
+	go func() {
+		defer sem.Release(1)
+		_ = ctx
+	}()
+}
+
+// ===== semaphore.Weighted.Acquire - SHOULD NOT REPORT =====
+
+// [GOOD]: Semaphore acquired with scope ctx
+//
+// Acquire uses the scope context, so admission stops when ctx is done.
+func goodAcquireWithCtx(ctx context.Context) {
+	sem := semaphore.NewWeighted(2)
+	if err := sem.Acquire(ctx, 1); err != nil {
+		return
+	}
+	go func() {
+		defer sem.Release(1)
+		_ = ctx
+	}()
+}
+
+// [GOOD]: Semaphore acquired with derived ctx
+//
+// Acquire uses a context derived from the scope context.
+func goodAcquireWithDerivedCtx(ctx context.Context) {
+	sem := semaphore.NewWeighted(2)
+	acquireCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if err := sem.Acquire(acquireCtx, 1); err != nil {
+		return
+	}
+	go func() {
+		defer sem.Release(1)
+		_ = ctx
+	}()
+}
+
+// [GOOD]: Semaphore TryAcquire
+//
+// TryAcquire never blocks, so admission needs no context.
+func goodTryAcquire(ctx context.Context) {
+	sem := semaphore.NewWeighted(2)
+	if !sem.TryAcquire(1) {
+		return
+	}
+	go func() {
+		defer sem.Release(1)
+		_ = ctx
+	}()
+}
+
+// [GOOD]: Semaphore without ctx in scope
+//
+// No ctx param - not checked
+func goodAcquireNoCtxParam() {
+	sem := semaphore.NewWeighted(2)
+	_ = sem.Acquire(context.Background(), 1)
+	sem.Release(1)
+}
+
+// ===== errgroup.Group.SetLimit - SHOULD REPORT =====
+
+// [BAD]: Limited errgroup Go
+//
+// Go blocks once SetLimit is reached, regardless of ctx.
+func badLimitedErrgroupGo(ctx context.Context) {
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(2)
+	for i := 0; i < 10; i++ {
+		g.Go(func() error { // want `errgroup.Group.Go\(\) blocks once SetLimit is reached and ignores context "ctx"; use TryGo so spawn admission respects cancellation`
+			return ctx.Err()
+		})
+	}
+	_ = g.Wait()
+}
+
+// ===== errgroup.Group.SetLimit - SHOULD NOT REPORT =====
+
+// [GOOD]: Limited errgroup TryGo
+//
+// TryGo never blocks, so admission needs no context.
+func goodLimitedErrgroupTryGo(ctx context.Context) {
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(2)
+	for i := 0; i < 10; i++ {
+		if !g.TryGo(func() error {
+			return ctx.Err()
+		}) {
+			break
+		}
+	}
+	_ = g.Wait()
+}
+
+// [GOOD]: Unlimited errgroup Go
+//
+// Go never blocks without SetLimit.
+func goodUnlimitedErrgroupGo(ctx context.Context) {
+	g, ctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		return ctx.Err()
+	})
+	_ = g.Wait()
+}
+
+// [GOOD]: SetLimit after errgroup Go
+//
+// Go runs before SetLimit is called, so it never blocks.
+func goodErrgroupGoBeforeSetLimit(ctx context.Context) {
+	g, ctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		return ctx.Err()
+	})
+	g.SetLimit(2)
+	_ = g.Wait()
+}
+
+// [GOOD]: SetLimit on another errgroup of the same name
+//
+// Only SetLimit calls on the same group in the same function count.
+func goodErrgroupSetLimitElsewhere(ctx context.Context) {
+	g, ctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		return ctx.Err()
+	})
+	_ = g.Wait()
+}
+
+//vt:helper
+func limitedErrgroupElsewhere() {
+	var g errgroup.Group
+	g.SetLimit(2)
+	_ = g.Wait()
+}
+
+// [GOOD]: SetLimit on a conditional path
+//
+// SetLimit does not run before Go on every path, so Go is not known to block.
+func goodErrgroupConditionalSetLimit(ctx context.Context, limited bool) {
+	g, ctx := errgroup.WithContext(ctx)
+	if limited {
+		g.SetLimit(2)
+	}
+	g.Go(func() error {
+		return ctx.Err()
+	})
+	_ = g.Wait()
+}
+
+// ===== Channel semaphore - SHOULD REPORT =====
+
+// [BAD]: Channel semaphore send before go
+//
+// Blocking send admits the goroutine without observing ctx.
+func badChannelSemaphore(ctx context.Context) {
+	sem := make(chan struct{}, 2)
+	for i := 0; i < 10; i++ {
+		sem <- struct{}{}
+		go func() { // want `go statement is admitted by a blocking send to "sem"; select on ctx.Done\(\) so spawn admission respects cancellation`
+			defer func() { <-sem }()
+			_ = ctx
+		}()
+	}
+}
+
+// [BAD]: Channel semaphore send before wg.Add and go
+//
+// Intermediate statements between the send and the go statement do not help.
+func badChannelSemaphoreWithWaitGroup(ctx context.Context) {
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, 2)
+	for i := 0; i < 10; i++ {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() { // want `go statement is admitted by a blocking send to "sem"; select on ctx.Done\(\) so spawn admission respects cancellation`
+			defer wg.Done()
+			defer func() { <-sem }()
+			_ = ctx
+		}()
+	}
+	wg.Wait()
+}
+
+// ===== Channel semaphore - SHOULD NOT REPORT =====
+
+// [GOOD]: Channel semaphore with select on ctx.Done
+//
+// Admission selects on ctx.Done(), so it stops when ctx is cancelled.
+func goodChannelSemaphoreSelect(ctx context.Context) {
+	sem := make(chan struct{}, 2)
+	for i := 0; i < 10; i++ {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			return
+		}
+		go func() {
+			defer func() { <-sem }()
+			_ = ctx
+		}()
+	}
+}
+
+// [GOOD]: Non-semaphore channel send before go
+//
+// Sending values (not empty structs) is not treated as admission.
+func goodValueChannelSend(ctx context.Context) {
+	results := make(chan int, 1)
+	results <- 1
+	go func() {
+		_ = ctx
+		<-results
+	}()
+}
+
+// [GOOD]: Unrelated channel send before go
+//
+// The goroutine does not release the channel, so the send is not its admission.
+func goodUnrelatedChannelSend(ctx context.Context) {
+	ready := make(chan struct{}, 1)
+	sem := make(chan struct{}, 2)
+	ready <- struct{}{}
+	go func() {
+		defer func() { <-sem }()
+		_ = ctx
+	}()
+}
+
+// [GOOD]: Channel semaphore released before go
+//
+// The slot is released again before the go statement, so it does not admit it.
+func goodChannelSemaphoreReleased(ctx context.Context) {
+	sem := make(chan struct{}, 2)
+	sem <- struct{}{}
+	<-sem
+	go func() {
+		defer func() { <-sem }()
+		_ = ctx
+	}()
+}
+
+// [GOOD]: Ignore directive on channel semaphore
+//
+// The //goroutinectx:ignore directive suppresses the warning.
+func goodIgnoreChannelSemaphore(ctx context.Context) {
+	sem := make(chan struct{}, 1)
+	sem <- struct{}{}
+	//goroutinectx:ignore admission - bounded by caller
+	go func() {
+		defer func() { <-sem }()
+		_ = ctx
+	}()
+}
@@ -0,0 +1,59 @@
+// Package baseline tests -baseline.
+// Findings recorded in goroutinectx-baseline.json are suppressed; new findings are reported.
+package baseline // want `baseline entry no longer occurs: goroutine in removedLegacyWorker: goroutine does not propagate context "ctx"`
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// ===== SHOULD REPORT =====
+
+// [BAD]: Finding not in baseline
+//
+// New findings are reported even when a baseline is configured.
+func badFindingNotInBaseline(ctx context.Context) {
+	go func() { // want `goroutine does not propagate context "ctx"`
+		fmt.Println("new")
+	}()
+}
+
+// [BAD]: Additional occurrence of baselined finding
+//
+// The baseline records how many times a finding occurs; extra occurrences are new.
+func badAdditionalOccurrenceOfBaselinedFinding(ctx context.Context) {
+	go func() {
+		fmt.Println("recorded")
+	}()
+	go func() { // want `goroutine does not propagate context "ctx"`
+		fmt.Println("recorded")
+	}()
+}
+
+// ===== SHOULD NOT REPORT =====
+
+// [GOOD]: Baselined finding
+//
+// A finding recorded in the baseline is suppressed.
+func goodBaselinedFinding(ctx context.Context) {
+	go func() {
+		fmt.Println("legacy")
+	}()
+}
+
+// [GOOD]: Baselined finding after line shift
+//
+// Baseline entries do not contain positions, so moving code does not invalidate them.
+func goodBaselinedFindingAfterLineShift(ctx context.Context) {
+	fmt.Println("inserted after the baseline was written")
+	fmt.Println("inserted after the baseline was written")
+
+	g := new(errgroup.Group)
+	g.Go(func() error {
+		fmt.Println("legacy")
+		return nil
+	})
+	_ = g.Wait()
+}
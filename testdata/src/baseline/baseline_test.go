@@ -0,0 +1,17 @@
+package baseline
+
+import (
+	"context"
+	"fmt"
+)
+
+// ===== SHOULD NOT REPORT =====
+
+// [GOOD]: Baselined finding in a test file
+//
+// Only the test variant sees this file: the package alone neither reports its entry as stale nor drops it.
+func helper(ctx context.Context) {
+	go func() {
+		fmt.Println("test helper")
+	}()
+}
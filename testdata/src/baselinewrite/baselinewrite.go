@@ -0,0 +1,30 @@
+// Package baselinewrite tests -write-baseline.
+// Findings are recorded into the baseline file instead of being reported.
+package baselinewrite
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/sync/errgroup"
+)
+
+type server struct{}
+
+func legacyWorker(ctx context.Context) {
+	go func() {
+		fmt.Println("recorded")
+	}()
+	go func() {
+		fmt.Println("recorded")
+	}()
+}
+
+func (s *server) handle(ctx context.Context) {
+	g := new(errgroup.Group)
+	g.Go(func() error {
+		fmt.Println("recorded")
+		return nil
+	})
+	_ = g.Wait()
+}
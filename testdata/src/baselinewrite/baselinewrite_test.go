@@ -0,0 +1,12 @@
+package baselinewrite
+
+import (
+	"context"
+	"fmt"
+)
+
+func helper(ctx context.Context) {
+	go func() {
+		fmt.Println("recorded")
+	}()
+}
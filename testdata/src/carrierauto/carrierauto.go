@@ -0,0 +1,58 @@
+// Package carrierauto contains test fixtures for -context-carriers-auto.
+// Types implementing context.Context are carriers without being listed.
+package carrierauto
+
+import (
+	"context"
+	"time"
+)
+
+// session embeds the context of the request it serves.
+type session struct {
+	context.Context
+	user string
+}
+
+// deadlineOnly implements only part of context.Context.
+type deadlineOnly struct{}
+
+//vt:helper
+func (deadlineOnly) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+// [BAD]: Struct embedding context.Context
+//
+// The session implements context.Context, but the goroutine ignores it.
+func badEmbeddedContext(s *session) {
+	go func() { // want `goroutine does not propagate context "s"`
+		doWork()
+	}()
+}
+
+// [GOOD]: Struct embedding context.Context
+//
+// The goroutine passes the session as its context.
+func goodEmbeddedContext(s *session) {
+	go func() {
+		work(s)
+	}()
+}
+
+// [GOOD]: Type partially implementing context.Context
+//
+// A type missing methods of context.Context is not a carrier.
+func goodPartialImplementer(d deadlineOnly) {
+	go func() {
+		doWork()
+	}()
+	_ = d
+}
+
+//vt:helper
+func work(ctx context.Context) {
+	_ = ctx
+}
+
+//vt:helper
+func doWork() {}
@@ -0,0 +1,111 @@
+// Package carrierdirective contains test fixtures for //goroutinectx:carrier directives.
+// Types marked as carriers are treated like context.Context without the -context-carriers flag.
+package carrierdirective
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/my-example-app/web"
+)
+
+// job carries the context of a background job.
+//
+//goroutinectx:carrier
+type job struct {
+	ctx  context.Context
+	name string
+}
+
+// task names an accessor that does not return a context.
+//
+//goroutinectx:carrier Name // want `goroutinectx:carrier accessor "Name" is not a method of task returning context.Context`
+type task struct {
+	name string
+}
+
+//vt:helper
+func (t task) Name() string {
+	return t.name
+}
+
+// [BAD]: Carrier declared by directive in another package
+//
+// The imported package declares its request type as a carrier, but the goroutine ignores it.
+func badImportedCarrier(r *web.Request) {
+	go func() { // want `goroutine does not propagate context "r"`
+		doWork()
+	}()
+}
+
+// [GOOD]: Carrier declared by directive in another package
+//
+// The goroutine uses the context of the request.
+func goodImportedCarrier(r *web.Request) {
+	go func() {
+		work(r.Context())
+	}()
+}
+
+// [BAD]: Carrier used without its accessor
+//
+// The directive names Context as the accessor; reading a parameter does not propagate the context.
+func badCarrierWithoutAccessor(r *web.Request) {
+	go func() { // want `goroutine does not propagate context "r"`
+		fmt.Println(r.Param("id"))
+	}()
+}
+
+// [GOOD]: Carrier passed on whole
+//
+// Passing the request on propagates its context, even without calling the accessor.
+func goodCarrierPassedOn(r *web.Request) {
+	go func() {
+		handle(r)
+	}()
+}
+
+// [GOOD]: Carrier directive with an invalid accessor in another package
+//
+// The imported type names an accessor not returning a context, so it is not a carrier.
+func goodImportedInvalidAccessor(s *web.Session) {
+	go func() {
+		doWork()
+	}()
+}
+
+// [BAD]: Carrier declared by directive in the same package
+//
+// The goroutine ignores the job carrying the context.
+func badLocalCarrier(j *job) {
+	go func() { // want `goroutine does not propagate context "j"`
+		doWork()
+	}()
+}
+
+// [GOOD]: Carrier declared by directive in the same package
+//
+// The goroutine passes the job on.
+func goodLocalCarrier(j *job) {
+	go func() {
+		runJob(j)
+	}()
+}
+
+//vt:helper
+func work(ctx context.Context) {
+	_ = ctx
+}
+
+//vt:helper
+func runJob(j *job) {
+	work(j.ctx)
+}
+
+//vt:helper
+func handle(r *web.Request) {
+	work(r.Context())
+}
+
+//vt:helper
+func doWork() {}
@@ -0,0 +1,89 @@
+// Package carrierinterface contains test fixtures for interface carriers.
+// Types implementing a carrier interface are carriers themselves.
+package carrierinterface
+
+import (
+	"context"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ContextHolder is implemented by request types carrying a context.
+//
+//goroutinectx:carrier Context
+type ContextHolder interface {
+	Context() context.Context
+}
+
+// request implements ContextHolder through its pointer.
+type request struct {
+	ctx context.Context
+}
+
+//vt:helper
+func (r *request) Context() context.Context {
+	return r.ctx
+}
+
+// testContext implements echo.Context by embedding it.
+type testContext struct {
+	echo.Context
+}
+
+// [BAD]: Implementation of a directive-declared carrier interface
+//
+// The request implements the carrier interface, but the goroutine ignores it.
+func badInterfaceImplementer(r *request) {
+	go func() { // want `goroutine does not propagate context "r"`
+		doWork()
+	}()
+}
+
+// [GOOD]: Implementation of a directive-declared carrier interface
+//
+// The goroutine uses the context of the request.
+func goodInterfaceImplementer(r *request) {
+	go func() {
+		work(r.Context())
+	}()
+}
+
+// [BAD]: Implementation of a flag-declared carrier interface
+//
+// A custom type implementing echo.Context is a carrier like echo.Context.
+func badEchoImplementer(c *testContext) {
+	go func() { // want `goroutine does not propagate context "c"`
+		doWork()
+	}()
+}
+
+// [GOOD]: Implementation of a flag-declared carrier interface
+//
+// The goroutine uses the context of the custom echo context.
+func goodEchoImplementer(c *testContext) {
+	go func() {
+		work(c.RealContext())
+	}()
+}
+
+// [GOOD]: Type not implementing the carrier interface
+//
+// A type without the accessor method is not a carrier, so there is no context to propagate.
+func goodNotImplementer(s *settings) {
+	go func() {
+		doWork()
+	}()
+	_ = s
+}
+
+type settings struct {
+	name string
+}
+
+//vt:helper
+func work(ctx context.Context) {
+	_ = ctx
+}
+
+//vt:helper
+func doWork() {}
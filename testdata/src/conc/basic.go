@@ -120,6 +120,43 @@ func goodResultContextPoolGo(ctx context.Context) {
 	_, _ = p.Wait()
 }
 
+// [BAD]: pool.ContextPool.Go - callback ignores its blank ctx param and captures outer ctx
+func badContextPoolGoBlankParam(ctx context.Context) {
+	p := &pool.ContextPool{}
+	p.Go(func(_ context.Context) error { // want `pool.ContextPool.Go\(\) closure should use its own context parameter instead of capturing "ctx"`
+		return doSomething(ctx)
+	})
+	_ = p.Wait()
+}
+
+// [BAD]: pool.ContextPool.Go - callback leaves its ctx param unused and captures outer ctx
+func badContextPoolGoUnusedParam(ctx context.Context) {
+	p := &pool.ContextPool{}
+	outer := ctx
+	p.Go(func(taskCtx context.Context) error { // want `pool.ContextPool.Go\(\) closure should use its own context parameter instead of capturing "outer"`
+		return doSomething(outer)
+	})
+	_ = p.Wait()
+}
+
+// [BAD]: pool.ResultContextPool[T].Go - callback ignores its ctx param
+func badResultContextPoolGoBlankParam(ctx context.Context) {
+	p := &pool.ResultContextPool[int]{}
+	p.Go(func(context.Context) (int, error) { // want `pool.ResultContextPool.Go\(\) closure should use its own context parameter instead of capturing "ctx"`
+		return 42, doSomething(ctx)
+	})
+	_, _ = p.Wait()
+}
+
+// [GOOD]: pool.ContextPool.Go - callback uses its own ctx param
+func goodContextPoolGoUsesParam(ctx context.Context) {
+	p := &pool.ContextPool{}
+	p.Go(func(ctx context.Context) error {
+		return doSomething(ctx)
+	})
+	_ = p.Wait()
+}
+
 // ===== pool.ErrorPool =====
 
 // [BAD]: pool.ErrorPool.Go without ctx
@@ -338,3 +375,9 @@ func goodMapperMapErr(ctx context.Context) {
 		return *item * 2, nil
 	})
 }
+
+// ===== Helpers =====
+
+func doSomething(ctx context.Context) error {
+	return ctx.Err()
+}
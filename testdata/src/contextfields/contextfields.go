@@ -0,0 +1,118 @@
+// Package contextfields contains test fixtures for -context-fields.
+// Context-typed struct fields are treated as context.
+package contextfields
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/sync/errgroup"
+)
+
+type job struct {
+	ctx  context.Context
+	name string
+}
+
+type task struct {
+	job *job
+}
+
+type plain struct {
+	name string
+}
+
+// ===== SHOULD NOT REPORT =====
+
+// [GOOD]: Receiver context field
+//
+// A method whose receiver has a context field is checked, and reading the field propagates it.
+func (j *job) goodReadsReceiverField() {
+	go func() {
+		work(j.ctx)
+	}()
+}
+
+// [GOOD]: Value receiver context field
+//
+// Value receivers with a context field start a scope as well.
+func (j job) goodValueReceiverField() {
+	go func() {
+		work(j.ctx)
+	}()
+}
+
+// [GOOD]: Captured struct context field
+//
+// A captured struct whose context field is read counts as capturing context.
+func goodCapturedStructField(ctx context.Context, j *job) {
+	go func() {
+		work(j.ctx)
+	}()
+}
+
+// [GOOD]: Goroutine reads nested context field
+//
+// Field paths through nested structs are followed.
+func goodNestedFieldPath(ctx context.Context, t task) {
+	go func() {
+		work(t.job.ctx)
+	}()
+}
+
+// [GOOD]: Nested goroutine reads captured context field
+//
+// A context field read in a nested closure propagates through the outer goroutine.
+func (j *job) goodNestedClosureReadsField() {
+	go func() {
+		go func() {
+			work(j.ctx)
+		}()
+	}()
+}
+
+// [GOOD]: Method without context field
+//
+// Methods whose receiver has no context field have no context scope.
+func (p *plain) goodNoContextField() {
+	go func() {
+		fmt.Println(p.name)
+	}()
+}
+
+// ===== SHOULD REPORT =====
+
+// [BAD]: Receiver context field
+//
+// The goroutine ignores the context field of its receiver.
+func (j *job) badIgnoresReceiverField() {
+	go func() { // want `goroutine does not propagate context "j.ctx"`
+		fmt.Println(j.name)
+	}()
+}
+
+// [BAD]: Captured struct context field
+//
+// Capturing a struct without reading its context field does not propagate context.
+func badCapturedStructOtherField(ctx context.Context, j *job) {
+	go func() { // want `goroutine does not propagate context "ctx"`
+		fmt.Println(j.name)
+	}()
+}
+
+// [BAD]: Errgroup callback ignores receiver context field
+//
+// Spawn API callbacks in such methods must use the context field too.
+func (j *job) badErrgroupIgnoresField() {
+	g := new(errgroup.Group)
+	g.Go(func() error { // want `errgroup.Group.Go\(\) closure should use context "j.ctx"`
+		fmt.Println(j.name)
+		return nil
+	})
+	_ = g.Wait()
+}
+
+//vt:helper
+func work(ctx context.Context) {
+	_ = ctx
+}
@@ -0,0 +1,153 @@
+// Package ctxfield contains test fixtures for the ctxfield checker.
+// Structs storing a context.Context must not be captured by goroutines.
+package ctxfield
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+)
+
+type job struct {
+	ctx  context.Context
+	name string
+}
+
+type plainJob struct {
+	name string
+}
+
+// allowedJob is listed in -ctxfield-allow.
+type allowedJob struct {
+	ctx context.Context
+}
+
+// requestScope is listed in -context-carriers.
+type requestScope struct {
+	ctx context.Context
+}
+
+// ===== SHOULD REPORT =====
+
+// [BAD]: Goroutine captures context-storing struct
+//
+// The goroutine keeps the stored request context alive after the caller returns.
+func badGoroutineCapturesStruct(j *job) {
+	go func() { // want `goroutine captures "j" of type job, which stores a context.Context in field "ctx"; pass the context explicitly instead`
+		fmt.Println(j.name)
+	}()
+}
+
+// [BAD]: Context-storing struct passed to goroutine function
+//
+// Passing the struct as an argument hands its context to the goroutine.
+func badStructArgument(j job) {
+	go run(&j) // want `goroutine captures "j" of type job, which stores a context.Context in field "ctx"; pass the context explicitly instead`
+}
+
+// [BAD]: Goroutine runs method of context-storing struct
+//
+// The receiver of a method run as a goroutine is handed to it.
+func badMethodReceiver(j *job) {
+	go j.process() // want `goroutine captures "j" of type job, which stores a context.Context in field "ctx"; pass the context explicitly instead`
+}
+
+// [BAD]: Errgroup callback captures context-storing struct
+//
+// Spawn API callbacks run on other goroutines like go statements.
+func badErrgroupCapturesStruct(ctx context.Context, j *job) {
+	g := new(errgroup.Group)
+	g.Go(func() error { // want `errgroup.Group.Go\(\) closure captures "j" of type job, which stores a context.Context in field "ctx"; pass the context explicitly instead`
+		return work(ctx, j.name)
+	})
+	_ = g.Wait()
+}
+
+// [BAD]: WaitGroup callback captures context-storing struct
+//
+// sync.WaitGroup.Go callbacks are registered spawn APIs too.
+func badWaitGroupCapturesStruct(j *job) {
+	var wg sync.WaitGroup
+	wg.Go(func() { // want `sync.WaitGroup.Go\(\) closure captures "j" of type job, which stores a context.Context in field "ctx"; pass the context explicitly instead`
+		fmt.Println(j.name)
+	})
+	wg.Wait()
+}
+
+// ===== SHOULD NOT REPORT =====
+
+// [GOOD]: Goroutine captures context-storing struct
+//
+// The goroutine receives the context explicitly and copies only the other fields.
+func goodGoroutineCapturesStruct(j *job) {
+	ctx, name := j.ctx, j.name
+	go func() {
+		_ = work(ctx, name)
+	}()
+}
+
+// [GOOD]: Struct without context field
+//
+// Structs without a context field can be shared freely.
+func goodPlainStruct(j *plainJob) {
+	go func() {
+		fmt.Println(j.name)
+	}()
+}
+
+// [GOOD]: Struct declared inside goroutine
+//
+// A struct built inside the goroutine is not captured from the caller.
+func goodStructDeclaredInside() {
+	go func() {
+		j := &job{ctx: context.Background(), name: "local"}
+		fmt.Println(j.name)
+	}()
+}
+
+// [GOOD]: Allow-listed struct type
+//
+// Types listed in -ctxfield-allow are not reported.
+func goodAllowListed(a *allowedJob) {
+	go func() {
+		fmt.Println(a.ctx)
+	}()
+}
+
+// [GOOD]: Carrier struct type
+//
+// Configured context carriers are meant to carry a context.
+func goodCarrier(s *requestScope) {
+	go func() {
+		fmt.Println(s.ctx)
+	}()
+}
+
+// [GOOD]: Ignored spawn
+//
+// Directives suppress the checker by name.
+func goodIgnored(j *job) {
+	//goroutinectx:ignore ctxfield - legacy worker
+	go func() {
+		fmt.Println(j.name)
+	}()
+}
+
+//vt:helper
+func (j *job) process() {
+	fmt.Println(j.name)
+}
+
+//vt:helper
+func run(j *job) {
+	fmt.Println(j.name)
+}
+
+//vt:helper
+func work(ctx context.Context, name string) error {
+	_ = ctx
+	_ = name
+	return nil
+}
@@ -0,0 +1,88 @@
+// Package deriverdirective contains test fixtures for //goroutinectx:deriver directives.
+// Functions marked as derivers are required without the -goroutine-deriver flag.
+package deriverdirective
+
+import (
+	"context"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/my-example-app/telemetry/otelx"
+)
+
+// [BAD]: Deriver declared by directive in another package
+//
+// The imported package declares its deriver, but the goroutine does not call it.
+func badImportedDirective(ctx context.Context) {
+	go func() { // want "goroutine should call deriverdirective.forkLocal,github.com/my-example-app/telemetry/otelx.Fork,github.com/my-example-app/telemetry/otelx.Tracer.Fork to derive context"
+		work(ctx)
+	}()
+}
+
+// [GOOD]: Deriver declared by directive in another package
+//
+// The goroutine calls the deriver declared by the imported package.
+func goodImportedDirective(ctx context.Context) {
+	go func() {
+		ctx := otelx.Fork(ctx)
+		work(ctx)
+	}()
+}
+
+// [BAD]: Deriver method declared by directive
+//
+// Methods of the tracer that are not marked do not derive the context.
+func badDirectiveMethod(ctx context.Context, tracer *otelx.Tracer) {
+	go func() { // want "goroutine should call deriverdirective.forkLocal,github.com/my-example-app/telemetry/otelx.Fork,github.com/my-example-app/telemetry/otelx.Tracer.Fork to derive context"
+		ctx := tracer.Attach(ctx)
+		work(ctx)
+	}()
+}
+
+// [GOOD]: Deriver method declared by directive
+//
+// The marked method derives the context.
+func goodDirectiveMethod(ctx context.Context, tracer *otelx.Tracer) {
+	go func() {
+		ctx := tracer.Fork(ctx)
+		work(ctx)
+	}()
+}
+
+// [GOOD]: Deriver declared by directive in the same package
+//
+// A function of the package marked as a deriver satisfies the requirement too.
+func goodLocalDirective(ctx context.Context) {
+	go func() {
+		ctx := forkLocal(ctx)
+		work(ctx)
+	}()
+}
+
+// [GOOD]: Deriver declared by directive in errgroup closure
+//
+// Declared derivers apply to spawn API closures as well.
+func goodErrgroupDirective(ctx context.Context) error {
+	g, ctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		ctx := otelx.Fork(ctx)
+		return workErr(ctx)
+	})
+	return g.Wait()
+}
+
+//vt:helper
+//goroutinectx:deriver
+func forkLocal(ctx context.Context) context.Context {
+	return ctx
+}
+
+//vt:helper
+func work(ctx context.Context) {
+	_ = ctx
+}
+
+//vt:helper
+func workErr(ctx context.Context) error {
+	return ctx.Err()
+}
@@ -0,0 +1,25 @@
+// Package deriverdirectivetransitive contains test fixtures for //goroutinectx:deriver
+// directives declared by a package that is only a transitive dependency.
+package deriverdirectivetransitive
+
+import (
+	"context"
+
+	"github.com/my-example-app/telemetry/otelxhttp"
+)
+
+// [GOOD]: Deriver declared by directive in a transitive dependency
+//
+// The package does not import the declaring package, so its deriver is not required.
+func goodTransitiveDirective(ctx context.Context) {
+	otelxhttp.Handle(ctx, func(ctx context.Context) {
+		go func() {
+			work(ctx)
+		}()
+	})
+}
+
+//vt:helper
+func work(ctx context.Context) {
+	_ = ctx
+}
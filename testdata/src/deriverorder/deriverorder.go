@@ -0,0 +1,176 @@
+// Package deriverorder contains test fixtures for deriver ordering.
+// Every use of the captured context must be dominated by the deriver call.
+package deriverorder
+
+import (
+	"context"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/my-example-app/telemetry/apm"
+)
+
+// [BAD]: Context used before deriving
+//
+// The first call still runs with the parent goroutine's context.
+func badUseBeforeDerive(ctx context.Context) {
+	go func() {
+		work(ctx) // want `goroutine uses context "ctx" before calling github.com/my-example-app/telemetry/apm.NewGoroutineContext to derive it`
+		ctx := apm.NewGoroutineContext(ctx)
+		work(ctx)
+	}()
+}
+
+// [GOOD]: Context used before deriving
+//
+// The deriver runs before every use.
+func goodUseBeforeDerive(ctx context.Context) {
+	go func() {
+		ctx := apm.NewGoroutineContext(ctx)
+		work(ctx)
+		work(ctx)
+	}()
+}
+
+// [BAD]: Context deferred before deriving
+//
+// Deferred call arguments are evaluated at the defer statement, before the deriver runs.
+func badDeferBeforeDerive(ctx context.Context) {
+	go func() {
+		defer work(ctx) // want `goroutine uses context "ctx" before calling github.com/my-example-app/telemetry/apm.NewGoroutineContext to derive it`
+		ctx := apm.NewGoroutineContext(ctx)
+		work(ctx)
+	}()
+}
+
+// [BAD]: Context passed to a nested goroutine before deriving
+//
+// The nested goroutine receives the parent goroutine's context.
+func badGoBeforeDerive(ctx context.Context) {
+	go func() {
+		go work(ctx) // want `goroutine uses context "ctx" before calling github.com/my-example-app/telemetry/apm.NewGoroutineContext to derive it`
+		ctx := apm.NewGoroutineContext(ctx)
+		work(ctx)
+	}()
+}
+
+// [GOOD]: Ignore directive at the reported use
+//
+// The directive above the reported use suppresses it, like one above the go statement.
+func goodIgnoreAtReportedUse(ctx context.Context) {
+	go func() {
+		//goroutinectx:ignore goroutinederive - the first call belongs to the parent span
+		work(ctx)
+		ctx := apm.NewGoroutineContext(ctx)
+		work(ctx)
+	}()
+}
+
+// [BAD]: Context derived on one branch only
+//
+// The use after the if statement is not dominated by the deriver call.
+func badConditionalDerive(ctx context.Context, traced bool) {
+	go func() {
+		if traced {
+			ctx = apm.NewGoroutineContext(ctx)
+		}
+		work(ctx) // want `goroutine uses context "ctx" before calling github.com/my-example-app/telemetry/apm.NewGoroutineContext to derive it`
+	}()
+}
+
+// [GOOD]: Context derived on one branch only
+//
+// The captured variable is reassigned unconditionally before use.
+func goodConditionalDerive(ctx context.Context) {
+	go func() {
+		ctx = apm.NewGoroutineContext(ctx)
+		work(ctx)
+	}()
+}
+
+// [BAD]: Original context used after deriving
+//
+// The derived context is only used for the first call.
+func badOriginalAfterDerive(ctx context.Context) {
+	go func() {
+		dctx := apm.NewGoroutineContext(ctx)
+		work(dctx)
+		work(ctx) // want `goroutine uses the original context "ctx" after calling github.com/my-example-app/telemetry/apm.NewGoroutineContext; use the derived context instead`
+	}()
+}
+
+// [GOOD]: Original context used after deriving
+//
+// Only the derived context is used after the deriver call.
+func goodOriginalAfterDerive(ctx context.Context) {
+	go func() {
+		dctx := apm.NewGoroutineContext(ctx)
+		work(dctx)
+		work(dctx)
+	}()
+}
+
+// [GOOD]: Context prepared for the deriver
+//
+// Values flowing into the deriver call may use the original context.
+func goodPreparedForDerive(ctx context.Context) {
+	go func() {
+		ctx := apm.NewGoroutineContext(withRequestID(ctx))
+		work(ctx)
+	}()
+}
+
+// [BAD]: Errgroup closure uses context before deriving
+//
+// Spawn API closures are checked for ordering like go statements.
+func badErrgroupUseBeforeDerive(ctx context.Context) {
+	g := new(errgroup.Group)
+	g.Go(func() error {
+		work(ctx) // want `errgroup\.Group\.Go\(\) closure uses context "ctx" before calling github.com/my-example-app/telemetry/apm.NewGoroutineContext to derive it`
+		ctx := apm.NewGoroutineContext(ctx)
+		work(ctx)
+		return nil
+	})
+	_ = g.Wait()
+}
+
+// [GOOD]: Errgroup closure uses context before deriving
+//
+// The errgroup closure derives before every use.
+func goodErrgroupUseBeforeDerive(ctx context.Context) {
+	g := new(errgroup.Group)
+	g.Go(func() error {
+		ctx := apm.NewGoroutineContext(ctx)
+		work(ctx)
+		return nil
+	})
+	_ = g.Wait()
+}
+
+// [BAD]: Spawner func argument uses original context after deriving
+//
+// Func arguments of spawners are checked for ordering like go statements.
+func badSpawnerOriginalAfterDerive(ctx context.Context) {
+	runAsync(func() {
+		dctx := apm.NewGoroutineContext(ctx)
+		work(dctx)
+		work(ctx) // want `runAsync\(\) func argument uses the original context "ctx" after calling github.com/my-example-app/telemetry/apm.NewGoroutineContext; use the derived context instead`
+	})
+}
+
+//goroutinectx:spawner //vt:helper
+func runAsync(fn func()) {
+	go fn()
+}
+
+//vt:helper
+func work(ctx context.Context) {
+	_ = ctx
+}
+
+//vt:helper
+func withRequestID(ctx context.Context) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, "id")
+}
+
+type requestIDKey struct{}
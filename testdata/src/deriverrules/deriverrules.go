@@ -0,0 +1,105 @@
+// Package deriverrules tests -goroutine-deriver-rules.
+// Deriver rules are selected per checker or per spawn API, falling back to -goroutine-deriver.
+package deriverrules
+
+import (
+	"context"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/my-example-app/telemetry/apm"
+)
+
+//goroutinectx:spawner //vt:helper
+func runInBackground(fn func()) {
+	go fn()
+}
+
+// ===== SHOULD REPORT =====
+
+// [BAD]: Goroutine falls back to default deriver
+//
+// No rule for goroutine - falls back to -goroutine-deriver.
+func badGoroutineWithoutDefaultDeriver(ctx context.Context) {
+	go func() { // want `goroutine should call github.com/my-example-app/telemetry/apm.NewGoroutineContext to derive context`
+		_ = ctx
+	}()
+}
+
+// [BAD]: Errgroup Go uses API rule deriver
+//
+// errgroup.Group.Go has its own rule, so the default deriver does not satisfy it.
+func badErrgroupGoCallsDefaultDeriver(ctx context.Context) {
+	g := new(errgroup.Group)
+	g.Go(func() error { // want `errgroup.Group.Go\(\) closure should use context "ctx" or call goroutine deriver`
+		_ = apm.NewGoroutineContext(context.Background())
+		return nil
+	})
+	_ = g.Wait()
+}
+
+// [BAD]: Errgroup TryGo falls back to default deriver
+//
+// errgroup.Group.TryGo has no rule, so it falls back to the default deriver.
+func badErrgroupTryGoCallsOtherAPIDeriver(ctx context.Context) {
+	g := new(errgroup.Group)
+	g.TryGo(func() error { // want `errgroup.Group.TryGo\(\) closure should use context "ctx" or call goroutine deriver`
+		_ = apm.NewSegmentContext(context.Background())
+		return nil
+	})
+	_ = g.Wait()
+}
+
+// [BAD]: Spawner with empty rule
+//
+// Empty rule for spawner - no deriver can satisfy it, only ctx.
+func badSpawnerWithEmptyRule(ctx context.Context) {
+	runInBackground(func() { // want `runInBackground\(\) func argument should use context "ctx"$`
+		_ = apm.NewGoroutineContext(context.Background())
+	})
+}
+
+// ===== SHOULD NOT REPORT =====
+
+// [GOOD]: Goroutine falls back to default deriver
+//
+// No rule for goroutine - falls back to -goroutine-deriver.
+func goodGoroutineWithDefaultDeriver(ctx context.Context) {
+	go func() {
+		ctx := apm.NewGoroutineContext(ctx)
+		_ = ctx
+	}()
+}
+
+// [GOOD]: Errgroup Go uses API rule deriver
+//
+// errgroup.Group.Go is satisfied by the deriver from its own rule.
+func goodErrgroupGoCallsAPIRuleDeriver(ctx context.Context) {
+	g := new(errgroup.Group)
+	g.Go(func() error {
+		_ = apm.NewSegmentContext(context.Background())
+		return nil
+	})
+	_ = g.Wait()
+}
+
+// [GOOD]: Errgroup TryGo falls back to default deriver
+//
+// errgroup.Group.TryGo has no rule, so the default deriver satisfies it.
+func goodErrgroupTryGoCallsDefaultDeriver(ctx context.Context) {
+	g := new(errgroup.Group)
+	g.TryGo(func() error {
+		_ = apm.NewGoroutineContext(context.Background())
+		return nil
+	})
+	_ = g.Wait()
+}
+
+// [GOOD]: Spawner with empty rule
+//
+// Empty rule for spawner - func argument uses ctx.
+func goodSpawnerWithEmptyRule(ctx context.Context) {
+	runInBackground(func() {
+		_ = ctx
+	})
+}
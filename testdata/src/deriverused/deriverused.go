@@ -0,0 +1,85 @@
+// Package deriverused contains test fixtures for -goroutine-deriver-used.
+// The context returned by the deriver must be used, not just the call made.
+package deriverused
+
+import (
+	"context"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/my-example-app/telemetry/apm"
+)
+
+// [BAD]: Deriver result discarded
+//
+// The deriver is called for its side effects only, so the goroutine runs untraced.
+func badResultDiscarded(ctx context.Context) {
+	go func() {
+		apm.NewGoroutineContext(ctx) // want `goroutine discards the context derived by github.com/my-example-app/telemetry/apm.NewGoroutineContext; pass it to subsequent calls`
+		doWork()
+	}()
+}
+
+// [GOOD]: Deriver result discarded
+//
+// The derived context is passed to subsequent calls.
+func goodResultDiscarded(ctx context.Context) {
+	go func() {
+		ctx := apm.NewGoroutineContext(ctx)
+		work(ctx)
+	}()
+}
+
+// [BAD]: Deriver result assigned but unused
+//
+// The derived context is assigned to a variable nobody uses.
+func badResultUnused(ctx context.Context) {
+	go func() {
+		dctx := apm.NewGoroutineContext(ctx) // want `goroutine discards the context derived by github.com/my-example-app/telemetry/apm.NewGoroutineContext; pass it to subsequent calls`
+		_ = dctx
+		doWork()
+	}()
+}
+
+// [GOOD]: Deriver result assigned but unused
+//
+// The derived context is used by a deferred closure.
+func goodResultUnused(ctx context.Context) {
+	go func() {
+		dctx := apm.NewGoroutineContext(ctx)
+		defer func() {
+			work(dctx)
+		}()
+		doWork()
+	}()
+}
+
+// [GOOD]: Deriver result replaces captured context
+//
+// Reassigning the captured variable replaces the original context.
+func goodReplacesCaptured(ctx context.Context) {
+	go func() {
+		ctx = apm.NewGoroutineContext(ctx)
+	}()
+}
+
+// [BAD]: Errgroup closure discards derived context
+//
+// Spawn API closures must use the derived context as well.
+func badErrgroupResultDiscarded(ctx context.Context) {
+	g := new(errgroup.Group)
+	g.Go(func() error {
+		apm.NewGoroutineContext(ctx) // want `errgroup\.Group\.Go\(\) closure discards the context derived by github.com/my-example-app/telemetry/apm.NewGoroutineContext; pass it to subsequent calls`
+		doWork()
+		return nil
+	})
+	_ = g.Wait()
+}
+
+//vt:helper
+func work(ctx context.Context) {
+	_ = ctx
+}
+
+//vt:helper
+func doWork() {}
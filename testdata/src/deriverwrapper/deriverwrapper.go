@@ -0,0 +1,98 @@
+// Package deriverwrapper contains test fixtures for deriver wrappers.
+// Functions that always derive the context they return satisfy the deriver requirement.
+package deriverwrapper
+
+import (
+	"context"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/my-example-app/telemetry/apm"
+	"github.com/my-example-app/telemetry/tracing"
+)
+
+type ctxKey struct{}
+
+// [BAD]: Deriver wrapper in another package
+//
+// The wrapper returns the parent context unchanged when tracing is disabled.
+func badImportedWrapper(ctx context.Context, enabled bool) {
+	go func() { // want "goroutine should call github.com/my-example-app/telemetry/apm.NewGoroutineContext to derive context"
+		ctx := tracing.MaybeSpan(ctx, enabled)
+		work(ctx)
+	}()
+}
+
+// [GOOD]: Deriver wrapper in another package
+//
+// The wrapper always derives the context it returns.
+func goodImportedWrapper(ctx context.Context) {
+	go func() {
+		ctx, end := tracing.StartSpan(ctx, "job")
+		defer end()
+		work(ctx)
+	}()
+}
+
+// [GOOD]: Deriver wrapper calling another wrapper
+//
+// Wrappers calling wrappers derive the context transitively.
+func goodNestedWrapper(ctx context.Context) {
+	go func() {
+		ctx := tracing.WorkerContext(ctx)
+		work(ctx)
+	}()
+}
+
+// [BAD]: Deriver wrapper in the same package
+//
+// The helper returns a derived context, but not one derived by the deriver.
+func badLocalWrapper(ctx context.Context) {
+	go func() { // want "goroutine should call github.com/my-example-app/telemetry/apm.NewGoroutineContext to derive context"
+		ctx := withValue(ctx)
+		work(ctx)
+	}()
+}
+
+// [GOOD]: Deriver wrapper in the same package
+//
+// The helper derives the context with the deriver before adding a value.
+func goodLocalWrapper(ctx context.Context) {
+	go func() {
+		ctx := withSpan(ctx)
+		work(ctx)
+	}()
+}
+
+// [GOOD]: Deriver wrapper in errgroup closure
+//
+// Wrappers satisfy the deriver requirement of spawn API closures too.
+func goodErrgroupWrapper(ctx context.Context) error {
+	g, ctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		ctx := tracing.WorkerContext(ctx)
+		return workErr(ctx)
+	})
+	return g.Wait()
+}
+
+//vt:helper
+func withSpan(ctx context.Context) context.Context {
+	ctx = apm.NewGoroutineContext(ctx)
+	return context.WithValue(ctx, ctxKey{}, "span")
+}
+
+//vt:helper
+func withValue(ctx context.Context) context.Context {
+	return context.WithValue(ctx, ctxKey{}, "value")
+}
+
+//vt:helper
+func work(ctx context.Context) {
+	_ = ctx
+}
+
+//vt:helper
+func workErr(ctx context.Context) error {
+	return ctx.Err()
+}
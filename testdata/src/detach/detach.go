@@ -0,0 +1,122 @@
+// Package detach contains test fixtures for -detach-funcs.
+// Closures using a context returned by a detach function detach intentionally.
+package detach
+
+import (
+	"context"
+	"net/http"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/my-example-app/ctxutil"
+)
+
+// Test cases with -goroutine-deriver=github.com/my-example-app/telemetry/apm.NewGoroutineContext
+// and -detach-funcs=context.WithoutCancel,github.com/my-example-app/ctxutil.Detach
+
+// ===== SHOULD NOT REPORT =====
+
+// [GOOD]: Goroutine calls detach function
+//
+// A goroutine that detaches the context itself does not need the deriver.
+func goodGoroutineCallsDetach(ctx context.Context) {
+	go func() {
+		work(ctxutil.Detach(ctx))
+	}()
+}
+
+// [GOOD]: Goroutine captures detached context
+//
+// A context detached before the go statement is accepted inside the goroutine.
+func goodGoroutineCapturesDetached(ctx context.Context) {
+	dctx := context.WithoutCancel(ctx)
+	go func() {
+		work(dctx)
+	}()
+}
+
+// [GOOD]: Nested goroutine captures detached context
+//
+// The detached context is traced through every enclosing closure.
+func goodNestedCapturesDetached(ctx context.Context) {
+	dctx := ctxutil.Detach(ctx)
+	go func() {
+		go func() {
+			work(dctx)
+		}()
+		work(dctx)
+	}()
+}
+
+// [GOOD]: Errgroup callback builds detached context
+//
+// An errgroup callback using a detached context does not need the outer context.
+func goodErrgroupDetached(ctx context.Context) {
+	g := new(errgroup.Group)
+	g.Go(func() error {
+		work(ctxutil.Detach(context.Background()))
+		return nil
+	})
+	_ = g.Wait()
+}
+
+// [GOOD]: Handler goroutine uses detached request context
+//
+// Values passed through a detach function are not bound to the request.
+func goodHandlerDetached(w http.ResponseWriter, r *http.Request) {
+	ctx := ctxutil.Detach(r.Context())
+	go func() {
+		work(ctx)
+	}()
+}
+
+// ===== SHOULD REPORT =====
+
+// [BAD]: Goroutine neither derives nor detaches
+//
+// Without a detach function, the goroutine must call the deriver.
+func badGoroutineNoDetach(ctx context.Context) {
+	go func() { // want "goroutine should call github.com/my-example-app/telemetry/apm.NewGoroutineContext to derive context"
+		work(ctx)
+	}()
+}
+
+// [BAD]: Goroutine captures conditionally detached context
+//
+// The context is detached only on one branch, so the goroutine may still use the request context.
+func badConditionallyDetached(ctx context.Context, detach bool) {
+	dctx := ctx
+	if detach {
+		dctx = ctxutil.Detach(ctx)
+	}
+	go func() { // want "goroutine should call github.com/my-example-app/telemetry/apm.NewGoroutineContext to derive context"
+		work(dctx)
+	}()
+}
+
+// [BAD]: Errgroup callback neither uses context nor detaches
+//
+// Without a detach function, the callback must use the outer context.
+func badErrgroupNoDetach(ctx context.Context) {
+	g := new(errgroup.Group)
+	g.Go(func() error { // want `errgroup.Group.Go\(\) closure should use context "ctx" or call goroutine deriver`
+		work(context.Background())
+		return nil
+	})
+	_ = g.Wait()
+}
+
+// [BAD]: Handler goroutine uses request context without detaching
+//
+// A context derived through a non-detach function stays bound to the request.
+func badHandlerNotDetached(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	go func() { // want `goroutine captures request-scoped "ctx" but is not awaited before the handler returns; detach it with context.WithoutCancel`
+		work(ctx)
+	}()
+}
+
+//vt:helper
+func work(ctx context.Context) {
+	_ = ctx
+}
@@ -0,0 +1,154 @@
+// Package generic tests generic spawners and derivers.
+// Generic functions and methods on generic types must match by their origin,
+// regardless of inferred or explicit instantiation.
+package generic
+
+import (
+	"context"
+	"fmt"
+)
+
+// ===== GENERIC SPAWNERS AND DERIVERS =====
+
+//goroutinectx:spawner //vt:helper
+func Spawn[T any](fn func() T) {
+	go fn()
+}
+
+//goroutinectx:spawner //vt:helper
+func SpawnPair[K comparable, V any](key K, fn func() V) {
+	go fn()
+}
+
+// Pool is a generic worker pool.
+type Pool[T any] struct{}
+
+//goroutinectx:spawner //vt:helper
+func (p *Pool[T]) Submit(fn func() T) {
+	go fn()
+}
+
+//vt:helper
+func Derive[T any](ctx context.Context, tag T) context.Context {
+	return ctx
+}
+
+// ===== SHOULD REPORT =====
+
+// [BAD]: Generic spawner with inferred type
+//
+// Generic spawner called with inferred type argument - func doesn't use ctx.
+func badSpawnInferred(ctx context.Context) {
+	Spawn(func() int { // want `Spawn\(\) func argument should use context "ctx" or call goroutine deriver`
+		return 1
+	})
+}
+
+// [BAD]: Generic spawner with explicit instantiation
+//
+// Generic spawner called with explicit type argument - func doesn't use ctx.
+func badSpawnExplicit(ctx context.Context) {
+	Spawn[int](func() int { // want `Spawn\(\) func argument should use context "ctx" or call goroutine deriver`
+		return 1
+	})
+}
+
+// [BAD]: Generic spawner with multiple type parameters
+//
+// Explicit instantiation with an index list expression - func doesn't use ctx.
+func badSpawnPairExplicit(ctx context.Context) {
+	SpawnPair[string, int]("key", func() int { // want `SpawnPair\(\) func argument should use context "ctx" or call goroutine deriver`
+		return 1
+	})
+}
+
+// [BAD]: Method on generic type spawner
+//
+// Spawner method on an instantiated generic receiver - func doesn't use ctx.
+func badPoolSubmit(ctx context.Context) {
+	p := &Pool[string]{}
+	p.Submit(func() string { // want `Submit\(\) func argument should use context "ctx" or call goroutine deriver`
+		return fmt.Sprint("no ctx")
+	})
+}
+
+// [BAD]: Goroutine without generic deriver
+//
+// Goroutine uses ctx but does not call the generic deriver.
+func badGoroutineWithoutGenericDeriver(ctx context.Context) {
+	go func() { // want `goroutine should call generic.Derive to derive context`
+		_ = ctx
+	}()
+}
+
+// ===== SHOULD NOT REPORT =====
+
+// [GOOD]: Generic spawner with inferred type
+//
+// Generic spawner called with inferred type argument - func uses ctx.
+func goodSpawnInferred(ctx context.Context) {
+	Spawn(func() int {
+		_ = ctx
+		return 1
+	})
+}
+
+// [GOOD]: Generic spawner with explicit instantiation
+//
+// Generic spawner called with explicit type argument - func uses ctx.
+func goodSpawnExplicit(ctx context.Context) {
+	Spawn[int](func() int {
+		_ = ctx
+		return 1
+	})
+}
+
+// [GOOD]: Generic spawner with multiple type parameters
+//
+// Explicit instantiation with an index list expression - func uses ctx.
+func goodSpawnPairExplicit(ctx context.Context) {
+	SpawnPair[string, int]("key", func() int {
+		_ = ctx
+		return 1
+	})
+}
+
+// [GOOD]: Method on generic type spawner
+//
+// Spawner method on an instantiated generic receiver - func uses ctx.
+func goodPoolSubmit(ctx context.Context) {
+	p := &Pool[string]{}
+	p.Submit(func() string {
+		return fmt.Sprint(ctx)
+	})
+}
+
+// [GOOD]: Goroutine calls generic deriver with inferred type
+//
+// Generic deriver is matched by its origin when the type argument is inferred.
+func goodGoroutineGenericDeriverInferred(ctx context.Context) {
+	go func() {
+		ctx := Derive(ctx, 1)
+		_ = ctx
+	}()
+}
+
+// [GOOD]: Goroutine calls generic deriver with explicit instantiation
+//
+// Generic deriver is matched by its origin when the type argument is explicit.
+func goodGoroutineGenericDeriverExplicit(ctx context.Context) {
+	go func() {
+		ctx := Derive[string](ctx, "tag")
+		_ = ctx
+	}()
+}
+
+// [GOOD]: Generic spawner func calls generic deriver
+//
+// Func argument satisfies the spawner check by calling the generic deriver.
+func goodSpawnCallsGenericDeriver(ctx context.Context) {
+	Spawn[int](func() int {
+		_ = Derive[int](ctx, 1)
+		return 1
+	})
+}
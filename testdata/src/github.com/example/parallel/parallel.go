@@ -0,0 +1,50 @@
+// Package parallel provides concurrent iterators for testing -concurrent-iterator.
+package parallel
+
+import (
+	"iter"
+	"sync"
+)
+
+// Each yields items concurrently; the loop body runs on worker goroutines.
+func Each[T any](items []T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		var (
+			mu sync.Mutex
+			wg sync.WaitGroup
+		)
+		for _, item := range items {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				mu.Lock()
+				defer mu.Unlock()
+				yield(item)
+			}()
+		}
+		wg.Wait()
+	}
+}
+
+// Group runs iterations on a bounded set of goroutines.
+type Group struct{}
+
+// Range yields indices concurrently.
+func (g *Group) Range(n int) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for i := 0; i < n; i++ {
+			go yield(i)
+		}
+	}
+}
+
+// Sequential yields items on the calling goroutine.
+func Sequential[T any](items []T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, item := range items {
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
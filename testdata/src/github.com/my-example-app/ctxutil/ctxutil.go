@@ -0,0 +1,9 @@
+// Package ctxutil provides application-specific context helpers.
+package ctxutil
+
+import "context"
+
+// Detach returns a context that keeps the values of ctx but is never cancelled.
+func Detach(ctx context.Context) context.Context {
+	return context.WithoutCancel(ctx)
+}
@@ -24,3 +24,9 @@ func StartSpan(ctx context.Context, name string) context.Context {
 	// In real implementation, this would create a span
 	return ctx
 }
+
+// NewSegmentContext creates a new context for a task segment within the current transaction.
+// This is used for errgroup tasks instead of NewGoroutineContext.
+func NewSegmentContext(ctx context.Context) context.Context {
+	return ctx
+}
@@ -0,0 +1,28 @@
+// Package otelx provides tracing helpers that declare their own derivers.
+// Consumers need no -goroutine-deriver flag to require them.
+package otelx
+
+import "context"
+
+// Fork derives a context for a new goroutine, linking its spans to the parent.
+//
+//goroutinectx:deriver
+func Fork(ctx context.Context) context.Context {
+	return ctx
+}
+
+// Tracer starts spans.
+type Tracer struct{}
+
+// Fork derives a context for a new goroutine with this tracer.
+//
+//goroutinectx:deriver
+func (*Tracer) Fork(ctx context.Context) context.Context {
+	return ctx
+}
+
+// Attach returns a context carrying the tracer. It is not a deriver.
+func (t *Tracer) Attach(ctx context.Context) context.Context {
+	_ = t
+	return ctx
+}
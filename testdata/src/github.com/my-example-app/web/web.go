@@ -0,0 +1,36 @@
+// Package web provides a request type for handlers.
+// It declares itself as a context carrier, so consumers need no -context-carriers flag.
+package web
+
+import "context"
+
+// Request is the request passed to handlers.
+//
+//goroutinectx:carrier Context
+type Request struct {
+	ctx    context.Context
+	params map[string]string
+}
+
+// Context returns the request context.
+func (r *Request) Context() context.Context {
+	return r.ctx
+}
+
+// Param returns a path parameter.
+func (r *Request) Param(name string) string {
+	return r.params[name]
+}
+
+// Session is the session of a request. Its directive names an accessor that
+// does not return a context, so it is not a carrier.
+//
+//goroutinectx:carrier ID
+type Session struct {
+	id string
+}
+
+// ID returns the session identifier.
+func (s *Session) ID() string {
+	return s.id
+}
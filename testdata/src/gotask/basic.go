@@ -57,6 +57,47 @@ func badDoAllFnsSettledDerivedParentCtx(ctx context.Context) {
 	)
 }
 
+// [BAD]: DoAllFnsSettled - func literal uses ctx before deriving
+//
+// Task function uses its context parameter before calling the deriver.
+func badDoAllFnsSettledUseBeforeDerive(ctx context.Context) {
+	_ = gotask.DoAllFnsSettled(
+		ctx,
+		func(ctx context.Context) error {
+			doSomethingWithContext(ctx) // want `gotask\.DoAllFnsSettled\(\) 2nd argument uses context "ctx" before calling github.com/my-example-app/telemetry/apm.NewGoroutineContext to derive it`
+			ctx = apm.NewGoroutineContext(ctx)
+			doSomethingWithContext(ctx)
+			return nil
+		},
+	)
+}
+
+// [BAD]: DoAllFnsSettled - func literal ignores its own ctx param
+//
+// Task function derives from the captured outer context instead of its own parameter.
+func badDoAllFnsSettledIgnoresOwnCtx(ctx context.Context) {
+	_ = gotask.DoAllFnsSettled( // want `gotask\.DoAllFnsSettled\(\) 2nd argument should use its own context parameter instead of capturing "ctx"`
+		ctx,
+		func(_ context.Context) error {
+			_ = apm.NewGoroutineContext(ctx)
+			return nil
+		},
+	)
+}
+
+// [BAD]: DoAllFnsSettled - func literal leaves its ctx param unused
+//
+// Task function declares a context parameter but only uses the outer one.
+func badDoAllFnsSettledUnusedOwnCtx(ctx context.Context) {
+	_ = gotask.DoAllFnsSettled( // want `gotask\.DoAllFnsSettled\(\) 2nd argument should use its own context parameter instead of capturing "ctx"`
+		ctx,
+		func(taskCtx context.Context) error {
+			_ = apm.NewGoroutineContext(ctx)
+			return nil
+		},
+	)
+}
+
 // ===== DoAllSettled with NewTask - SHOULD REPORT =====
 
 // [BAD]: DoAllSettled - NewTask with deriver
@@ -0,0 +1,302 @@
+// Package handler contains test fixtures for the handler checker.
+// Goroutines capturing a request context must not outlive the handler.
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// ===== net/http handlers - SHOULD REPORT =====
+
+// [BAD]: Goroutine uses r.Context() after handler returns
+//
+// The request context is cancelled when ServeHTTP returns, so the fire-and-forget goroutine is cancelled too.
+func badRequestContextInGoroutine(w http.ResponseWriter, r *http.Request) {
+	go func() { // want `goroutine captures request-scoped "r" but is not awaited before the handler returns; detach it with context.WithoutCancel`
+		fmt.Println(r.Context())
+	}()
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// [BAD]: Goroutine uses context derived from request
+//
+// A context derived from r.Context() is cancelled together with the request.
+func badDerivedRequestContext(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	go func() { // want `goroutine captures request-scoped "ctx" but is not awaited before the handler returns; detach it with context.WithoutCancel`
+		fmt.Println(ctx)
+	}()
+}
+
+// [BAD]: Request context passed to goroutine function
+//
+// Passing the request context as an argument binds the goroutine to the request.
+func badRequestContextArgument(w http.ResponseWriter, r *http.Request) {
+	go process(r.Context()) // want `goroutine captures request-scoped "r" but is not awaited before the handler returns; detach it with context.WithoutCancel`
+}
+
+type server struct{}
+
+// [BAD]: ServeHTTP method spawns request-bound goroutine
+//
+// ServeHTTP methods are handlers like http.HandlerFunc.
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	go func() { // want `goroutine captures request-scoped "ctx" but is not awaited before the handler returns; detach it with context.WithoutCancel`
+		fmt.Println(ctx)
+	}()
+}
+
+// [BAD]: Handler func literal spawns request-bound goroutine
+//
+// Func literals with the http.HandlerFunc signature are handlers.
+func badHandlerFuncLiteral() {
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		go func() { // want `goroutine captures request-scoped "r" but is not awaited before the handler returns; detach it with context.WithoutCancel`
+			fmt.Println(r.Context())
+		}()
+	})
+}
+
+// [BAD]: Errgroup closure not awaited
+//
+// Spawn APIs are checked like go statements when the group is never waited.
+func badErrgroupNotAwaited(w http.ResponseWriter, r *http.Request) {
+	var g errgroup.Group
+	g.Go(func() error { // want `errgroup.Group.Go\(\) closure captures request-scoped "r" but is not awaited before the handler returns; detach it with context.WithoutCancel`
+		fmt.Println(r.Context())
+		return nil
+	})
+}
+
+// [BAD]: Receive from an unrelated channel
+//
+// Only receives from a channel the goroutine sends on or closes join it.
+func badUnrelatedChannelReceive(w http.ResponseWriter, r *http.Request) {
+	ready := make(chan struct{})
+	done := make(chan struct{})
+	go func() { // want `goroutine captures request-scoped "r" but is not awaited before the handler returns; detach it with context.WithoutCancel`
+		defer close(done)
+		process(r.Context())
+	}()
+	<-ready
+}
+
+// [BAD]: Wait on another WaitGroup
+//
+// Only the WaitGroup the goroutine calls Done on joins it.
+func badOtherWaitGroup(w http.ResponseWriter, r *http.Request) {
+	var wg, other sync.WaitGroup
+	wg.Add(1)
+	go func() { // want `goroutine captures request-scoped "r" but is not awaited before the handler returns; detach it with context.WithoutCancel`
+		defer wg.Done()
+		process(r.Context())
+	}()
+	other.Wait()
+}
+
+// [BAD]: Select without the goroutine's channel
+//
+// A select that never receives from the goroutine's channel does not join it.
+func badUnrelatedSelect(w http.ResponseWriter, r *http.Request) {
+	ready := make(chan struct{})
+	done := make(chan error, 1)
+	go func() { // want `goroutine captures request-scoped "r" but is not awaited before the handler returns; detach it with context.WithoutCancel`
+		done <- work(r.Context())
+	}()
+	select {
+	case <-ready:
+	default:
+	}
+}
+
+// ===== net/http handlers - SHOULD NOT REPORT =====
+
+// [GOOD]: Goroutine uses r.Context() after handler returns
+//
+// The context is detached with context.WithoutCancel before the goroutine starts.
+func goodRequestContextInGoroutine(w http.ResponseWriter, r *http.Request) {
+	ctx := context.WithoutCancel(r.Context())
+	go func() {
+		fmt.Println(ctx)
+	}()
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// [GOOD]: Goroutine uses context derived from request
+//
+// The goroutine detaches the request context itself.
+func goodDerivedRequestContext(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	go func() {
+		fmt.Println(context.WithoutCancel(ctx))
+	}()
+}
+
+// [GOOD]: Request context passed to goroutine function
+//
+// The handler waits for the goroutine, so the request is still alive.
+func goodRequestContextArgument(w http.ResponseWriter, r *http.Request) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		process(r.Context())
+	}()
+	wg.Wait()
+}
+
+// [GOOD]: Goroutine joined through channel
+//
+// Receiving the goroutine's result awaits it before returning.
+func goodJoinedThroughChannel(w http.ResponseWriter, r *http.Request) {
+	done := make(chan error, 1)
+	go func() {
+		done <- work(r.Context())
+	}()
+	if err := <-done; err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+// [GOOD]: Goroutine joined through closed channel in select
+//
+// Selecting on the channel the goroutine closes awaits it.
+func goodJoinedThroughSelect(w http.ResponseWriter, r *http.Request) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		process(r.Context())
+	}()
+	select {
+	case <-done:
+	case <-r.Context().Done():
+	}
+}
+
+// [GOOD]: Goroutine function joined through WaitGroup argument
+//
+// The WaitGroup passed to the goroutine function is waited before returning.
+func goodJoinedThroughWaitGroupArgument(w http.ResponseWriter, r *http.Request) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go processWithWaitGroup(r.Context(), &wg)
+	wg.Wait()
+}
+
+// [GOOD]: Goroutine reads request fields only
+//
+// Reading fields other than the context does not bind the goroutine to cancellation.
+func goodRequestFieldsOnly(w http.ResponseWriter, r *http.Request) {
+	path := r.URL.Path
+	go func() {
+		fmt.Println(path, r.Method)
+	}()
+}
+
+// [GOOD]: Errgroup closure not awaited
+//
+// The handler waits for the group before returning.
+func goodErrgroupNotAwaited(w http.ResponseWriter, r *http.Request) {
+	g, ctx := errgroup.WithContext(r.Context())
+	g.Go(func() error {
+		return work(ctx)
+	})
+	_ = g.Wait()
+}
+
+// [GOOD]: Deferred wait
+//
+// A deferred Wait runs before the handler returns.
+func goodDeferredWait(w http.ResponseWriter, r *http.Request) {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		process(r.Context())
+	}()
+}
+
+// ===== gRPC service methods =====
+
+type UnimplementedGreeterServer struct{}
+
+type HelloRequest struct{}
+
+type HelloReply struct{}
+
+type greeter struct {
+	UnimplementedGreeterServer
+}
+
+// [BAD]: gRPC method spawns request-bound goroutine
+//
+// The incoming context of a unary gRPC method is cancelled when the method returns.
+func (g *greeter) SayHello(ctx context.Context, req *HelloRequest) (*HelloReply, error) {
+	go func() { // want `goroutine captures request-scoped "ctx" but is not awaited before the handler returns; detach it with context.WithoutCancel`
+		_ = work(ctx)
+	}()
+	return &HelloReply{}, nil
+}
+
+// [GOOD]: gRPC method spawns request-bound goroutine
+//
+// The incoming context is detached before the goroutine starts.
+func (g *greeter) SayGoodbye(ctx context.Context, req *HelloRequest) (*HelloReply, error) {
+	detached := context.WithoutCancel(ctx)
+	go func() {
+		_ = work(detached)
+	}()
+	return &HelloReply{}, nil
+}
+
+// ===== Configured handlers (-handler-funcs) =====
+
+//vt:helper
+func route(path string, fn func(ctx context.Context) error) {}
+
+// [BAD]: Func literal registered with configured router
+//
+// Func literals passed to a -handler-funcs registration function are handlers.
+func badConfiguredRoute() {
+	route("/", func(ctx context.Context) error {
+		go func() { // want `goroutine captures request-scoped "ctx" but is not awaited before the handler returns; detach it with context.WithoutCancel`
+			_ = work(ctx)
+		}()
+		return nil
+	})
+}
+
+// [GOOD]: Func literal registered with configured router
+//
+// Functions not registered as handlers may start long-lived goroutines.
+func goodConfiguredRoute(ctx context.Context) {
+	go func() {
+		_ = work(ctx)
+	}()
+}
+
+// [GOOD]: Ignored fire-and-forget goroutine
+//
+// The handler checker can be suppressed with an ignore directive.
+func goodIgnoredFireAndForget(w http.ResponseWriter, r *http.Request) {
+	//goroutinectx:ignore handler - cancellation is intended
+	go func() {
+		fmt.Println(r.Context())
+	}()
+}
+
+//vt:helper
+func process(ctx context.Context) {}
+
+//vt:helper
+func processWithWaitGroup(ctx context.Context, wg *sync.WaitGroup) { defer wg.Done() }
+
+//vt:helper
+func work(ctx context.Context) error { return nil }
@@ -0,0 +1,153 @@
+// Package ignoredirective tests block-scoped and function-level ignore directives.
+// Regions and function doc comments suppress every finding they cover.
+package ignoredirective
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// ===== ignore-begin / ignore-end - SHOULD REPORT =====
+
+// [BAD]: Goroutine after ignore-end
+//
+// Only lines between ignore-begin and ignore-end are covered.
+func badGoroutineAfterIgnoreEnd(ctx context.Context) {
+	//goroutinectx:ignore-begin - legacy fan-out
+	go func() {
+		fmt.Println("ignored")
+	}()
+	//goroutinectx:ignore-end
+
+	go func() { // want `goroutine does not propagate context "ctx"`
+		fmt.Println("reported")
+	}()
+}
+
+// [BAD]: Region for another checker
+//
+// Checker-specific region does not cover other checkers and is reported as unused.
+func badRegionForAnotherChecker(ctx context.Context) {
+	//goroutinectx:ignore-begin errgroup // want `unused goroutinectx:ignore directive for checker\(s\): errgroup`
+	go func() { // want `goroutine does not propagate context "ctx"`
+		fmt.Println("reported")
+	}()
+	//goroutinectx:ignore-end
+}
+
+// [BAD]: Stray ignore-end
+//
+// An ignore-end closing no region is reported.
+func badStrayIgnoreEnd(ctx context.Context) {
+	go func() { // want `goroutine does not propagate context "ctx"`
+		fmt.Println("reported")
+	}()
+	//goroutinectx:ignore-end // want `goroutinectx:ignore-end directive without a matching goroutinectx:ignore-begin`
+}
+
+// ===== ignore-begin / ignore-end - SHOULD NOT REPORT =====
+
+// [GOOD]: Goroutines inside ignore region
+//
+// Every finding between ignore-begin and ignore-end is suppressed.
+func goodGoroutinesInsideIgnoreRegion(ctx context.Context) {
+	//goroutinectx:ignore-begin - legacy fan-out
+	go func() {
+		fmt.Println("first")
+	}()
+
+	g := new(errgroup.Group)
+	g.Go(func() error {
+		fmt.Println("second")
+		return nil
+	})
+	_ = g.Wait()
+	//goroutinectx:ignore-end
+}
+
+// [GOOD]: Checker-specific ignore region
+//
+// Region limited to the goroutine checker suppresses goroutine findings.
+func goodCheckerSpecificIgnoreRegion(ctx context.Context) {
+	//goroutinectx:ignore-begin goroutine - legacy fan-out
+	go func() {
+		fmt.Println("first")
+	}()
+	go func() {
+		fmt.Println("second")
+	}()
+	//goroutinectx:ignore-end
+}
+
+// [GOOD]: Nested ignore regions
+//
+// Each ignore-end closes the most recent ignore-begin.
+func goodNestedIgnoreRegions(ctx context.Context) {
+	//goroutinectx:ignore-begin goroutine - outer
+	go func() {
+		fmt.Println("outer")
+	}()
+	//goroutinectx:ignore-begin errgroup - inner
+	g := new(errgroup.Group)
+	g.Go(func() error {
+		fmt.Println("inner")
+		return nil
+	})
+	//goroutinectx:ignore-end
+	go func() {
+		fmt.Println("outer again")
+	}()
+	//goroutinectx:ignore-end
+	_ = g.Wait()
+}
+
+// ===== Function-level ignore - SHOULD REPORT =====
+
+// [BAD]: Ignore directive inside function body
+//
+// A directive in the body covers only the next line, not the rest of the function.
+func badIgnoreInsideFunctionBody(ctx context.Context) {
+	//goroutinectx:ignore - first only
+	go func() {
+		fmt.Println("ignored")
+	}()
+	go func() { // want `goroutine does not propagate context "ctx"`
+		fmt.Println("reported")
+	}()
+}
+
+// ===== Function-level ignore - SHOULD NOT REPORT =====
+
+// [GOOD]: Function-level ignore directive
+//
+// An ignore directive in the doc comment covers the whole function body.
+//
+//goroutinectx:ignore - legacy worker, rewritten in v2
+func goodFunctionLevelIgnore(ctx context.Context) {
+	go func() {
+		fmt.Println("first")
+	}()
+
+	g := new(errgroup.Group)
+	g.Go(func() error {
+		fmt.Println("second")
+		return nil
+	})
+	_ = g.Wait()
+}
+
+// [GOOD]: Checker-specific function-level ignore directive
+//
+// A checker-specific doc comment directive covers that checker in the whole body.
+//
+//goroutinectx:ignore goroutine - legacy worker
+func goodCheckerSpecificFunctionLevelIgnore(ctx context.Context) {
+	go func() {
+		fmt.Println("first")
+	}()
+	go func() {
+		fmt.Println("second")
+	}()
+}
@@ -0,0 +1,16 @@
+package ignoredirective
+
+import (
+	"context"
+	"fmt"
+)
+
+// [BAD]: Unterminated ignore region
+//
+// An ignore-begin without ignore-end covers the rest of the file and is reported.
+func badUnterminatedIgnoreRegion(ctx context.Context) {
+	//goroutinectx:ignore-begin - legacy fan-out // want `goroutinectx:ignore-begin directive without a matching goroutinectx:ignore-end; it covers the rest of the file`
+	go func() {
+		fmt.Println("ignored")
+	}()
+}
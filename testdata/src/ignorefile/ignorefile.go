@@ -0,0 +1,40 @@
+//goroutinectx:ignore-file goroutine - generated-ish legacy code
+
+// Package ignorefile tests file-level ignore directives.
+// The //goroutinectx:ignore-file directive covers every line of its file.
+package ignorefile
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// ===== SHOULD REPORT =====
+
+// [BAD]: Other checker in ignored file
+//
+// File-level directive limited to the goroutine checker does not cover errgroup.
+func badOtherCheckerInIgnoredFile(ctx context.Context) {
+	g := new(errgroup.Group)
+	g.Go(func() error { // want `errgroup.Group.Go\(\) closure should use context "ctx"`
+		fmt.Println("reported")
+		return nil
+	})
+	_ = g.Wait()
+}
+
+// ===== SHOULD NOT REPORT =====
+
+// [GOOD]: Goroutines in ignored file
+//
+// File-level directive suppresses goroutine findings anywhere in the file.
+func goodGoroutinesInIgnoredFile(ctx context.Context) {
+	go func() {
+		fmt.Println("first")
+	}()
+	go func() {
+		fmt.Println("second")
+	}()
+}
@@ -0,0 +1,17 @@
+package ignorefile
+
+import (
+	"context"
+	"fmt"
+)
+
+// ===== SHOULD REPORT =====
+
+// [BAD]: Goroutine in another file of the package
+//
+// File-level directive does not cover other files of the same package.
+func badGoroutineInAnotherFile(ctx context.Context) {
+	go func() { // want `goroutine does not propagate context "ctx"`
+		fmt.Println("reported")
+	}()
+}
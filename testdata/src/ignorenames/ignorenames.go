@@ -0,0 +1,83 @@
+// Package ignorenames tests checker name validation in ignore directives.
+// Unknown names are reported with suggestions; errgroup remains an alias for conc.
+package ignorenames
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sourcegraph/conc"
+	"golang.org/x/sync/errgroup"
+)
+
+// ===== Unknown checker names - SHOULD REPORT =====
+
+// [BAD]: Misspelled checker name
+//
+// A typo is reported with a suggestion and does not suppress the warning.
+func badMisspelledCheckerName(ctx context.Context) {
+	//goroutinectx:ignore gorutine // want `unknown checker "gorutine" in goroutinectx:ignore directive \(did you mean "goroutine"\?\)`
+	go func() { // want `goroutine does not propagate context "ctx"`
+		fmt.Println("reported")
+	}()
+}
+
+// [BAD]: Unknown checker name without suggestion
+//
+// A name far from every registered checker is reported without a suggestion.
+func badUnknownCheckerName(ctx context.Context) {
+	//goroutinectx:ignore linter // want `unknown checker "linter" in goroutinectx:ignore directive$`
+	go func() { // want `goroutine does not propagate context "ctx"`
+		fmt.Println("reported")
+	}()
+}
+
+// [BAD]: Misspelled name next to a valid name
+//
+// The valid name still suppresses its checker; only the typo is reported.
+func badMisspelledNameNextToValidName(ctx context.Context) {
+	//goroutinectx:ignore goroutine,errgrup // want `unknown checker "errgrup" in goroutinectx:ignore directive \(did you mean "errgroup"\?\)`
+	go func() {
+		fmt.Println("ignored")
+	}()
+}
+
+// ===== conc checker name - SHOULD REPORT =====
+
+// [BAD]: Conc name on errgroup call
+//
+// The conc name does not cover errgroup findings and is reported as unused.
+func badConcNameOnErrgroupCall(ctx context.Context) {
+	g := new(errgroup.Group)
+	//goroutinectx:ignore conc // want `unused goroutinectx:ignore directive for checker\(s\): conc`
+	g.Go(func() error { // want `errgroup.Group.Go\(\) closure should use context "ctx"`
+		return nil
+	})
+	_ = g.Wait()
+}
+
+// ===== conc checker name - SHOULD NOT REPORT =====
+
+// [GOOD]: Conc name on conc call
+//
+// conc findings are filed under their own checker name.
+func goodConcNameOnConcCall(ctx context.Context) {
+	var wg conc.WaitGroup
+	//goroutinectx:ignore conc - fire-and-forget
+	wg.Go(func() {
+		fmt.Println("ignored")
+	})
+	wg.Wait()
+}
+
+// [GOOD]: Errgroup alias on conc call
+//
+// errgroup is kept as a compatible alias that also covers conc findings.
+func goodErrgroupAliasOnConcCall(ctx context.Context) {
+	var wg conc.WaitGroup
+	//goroutinectx:ignore errgroup - fire-and-forget
+	wg.Go(func() {
+		fmt.Println("ignored")
+	})
+	wg.Wait()
+}
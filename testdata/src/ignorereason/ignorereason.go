@@ -0,0 +1,74 @@
+// Package ignorereason tests -require-ignore-reason.
+// Every ignore directive must explain why with " - reason".
+package ignorereason
+
+import (
+	"context"
+	"fmt"
+)
+
+// ===== SHOULD REPORT =====
+
+// [BAD]: Required reason on ignore directive
+//
+// Directive without " - reason" is reported even though it suppresses the warning.
+func badIgnoreWithoutReason(ctx context.Context) {
+	//goroutinectx:ignore // want `goroutinectx:ignore directive should have a reason`
+	go func() {
+		fmt.Println("ignored")
+	}()
+}
+
+// [BAD]: Required reason on checker-specific ignore directive
+//
+// Checker names alone do not count as a reason.
+func badCheckerSpecificIgnoreWithoutReason(ctx context.Context) {
+	//goroutinectx:ignore goroutine // want `goroutinectx:ignore directive should have a reason`
+	go func() {
+		fmt.Println("ignored")
+	}()
+}
+
+// [BAD]: Required reason on ignore region
+//
+// Region directives require a reason too.
+func badIgnoreRegionWithoutReason(ctx context.Context) {
+	//goroutinectx:ignore-begin goroutine // want `goroutinectx:ignore-begin directive should have a reason`
+	go func() {
+		fmt.Println("ignored")
+	}()
+	//goroutinectx:ignore-end
+}
+
+// ===== SHOULD NOT REPORT =====
+
+// [GOOD]: Required reason on ignore directive
+//
+// Directive with " - reason" is accepted.
+func goodIgnoreWithReason(ctx context.Context) {
+	//goroutinectx:ignore - fire-and-forget metrics flush
+	go func() {
+		fmt.Println("ignored")
+	}()
+}
+
+// [GOOD]: Required reason on checker-specific ignore directive
+//
+// Checker names followed by " - reason" are accepted.
+func goodCheckerSpecificIgnoreWithReason(ctx context.Context) {
+	//goroutinectx:ignore goroutine - fire-and-forget metrics flush
+	go func() {
+		fmt.Println("ignored")
+	}()
+}
+
+// [GOOD]: Required reason on ignore region
+//
+// Region directive with " - reason" is accepted; ignore-end needs no reason.
+func goodIgnoreRegionWithReason(ctx context.Context) {
+	//goroutinectx:ignore-begin goroutine - legacy fan-out
+	go func() {
+		fmt.Println("ignored")
+	}()
+	//goroutinectx:ignore-end
+}
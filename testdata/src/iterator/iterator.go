@@ -0,0 +1,114 @@
+// Package iterator tests the -concurrent-iterator flag.
+// Range-over-func loops over registered iterators run their body on another goroutine.
+package iterator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/example/parallel"
+)
+
+// ===== SHOULD REPORT =====
+
+// [BAD]: Range over concurrent iterator without ctx
+//
+// Loop body runs on worker goroutines but does not use ctx.
+func badRangeEach(ctx context.Context) {
+	for item := range parallel.Each([]int{1, 2, 3}) { // want `parallel.Each\(\) loop body should use context "ctx"`
+		fmt.Println(item)
+	}
+}
+
+// [BAD]: Range over concurrent iterator method without ctx
+//
+// Method-based iterator body does not use ctx.
+func badRangeGroupMethod(ctx context.Context) {
+	g := &parallel.Group{}
+	for i := range g.Range(3) { // want `parallel.Group.Range\(\) loop body should use context "ctx"`
+		fmt.Println(i)
+	}
+}
+
+// [BAD]: Range over iterator stored in variable
+//
+// Iterator is assigned to a variable before ranging.
+func badRangeVariable(ctx context.Context) {
+	seq := parallel.Each([]string{"a", "b"})
+	for s := range seq { // want `parallel.Each\(\) loop body should use context "ctx"`
+		fmt.Println(s)
+	}
+}
+
+// ===== SHOULD NOT REPORT =====
+
+// [GOOD]: Range over concurrent iterator with ctx
+//
+// Loop body uses ctx.
+func goodRangeEach(ctx context.Context) {
+	for item := range parallel.Each([]int{1, 2, 3}) {
+		fmt.Println(ctx, item)
+	}
+}
+
+// [GOOD]: Range body uses ctx in nested closure
+//
+// SSA FreeVars propagation detects context captured in nested closures, as for goroutines.
+func goodRangeNestedClosure(ctx context.Context) {
+	for item := range parallel.Each([]int{1, 2, 3}) {
+		func() {
+			_ = ctx
+		}()
+		_ = item
+	}
+}
+
+// [GOOD]: Range over concurrent iterator method with ctx
+//
+// Method-based iterator body uses ctx.
+func goodRangeGroupMethod(ctx context.Context) {
+	g := &parallel.Group{}
+	for i := range g.Range(3) {
+		if ctx.Err() != nil {
+			return
+		}
+		_ = i
+	}
+}
+
+// [GOOD]: Range over sequential iterator
+//
+// Unregistered iterators run on the calling goroutine - not checked.
+func goodRangeSequential(ctx context.Context) {
+	for item := range parallel.Sequential([]int{1, 2, 3}) {
+		fmt.Println(item)
+	}
+}
+
+// [GOOD]: Range over slice
+//
+// Regular range loops are not checked.
+func goodRangeSlice(ctx context.Context) {
+	for _, item := range []int{1, 2, 3} {
+		fmt.Println(item)
+	}
+}
+
+// [GOOD]: No ctx param
+//
+// No ctx param - not checked
+func goodNoCtxParam() {
+	for item := range parallel.Each([]int{1, 2, 3}) {
+		fmt.Println(item)
+	}
+}
+
+// [GOOD]: Ignore directive on concurrent iterator
+//
+// The //goroutinectx:ignore directive suppresses the warning.
+func goodIgnoreRangeEach(ctx context.Context) {
+	//goroutinectx:ignore iterator - body is CPU-bound only
+	for item := range parallel.Each([]int{1, 2, 3}) {
+		fmt.Println(item)
+	}
+}
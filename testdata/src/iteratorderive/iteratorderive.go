@@ -0,0 +1,69 @@
+// Package iteratorderive tests the -concurrent-iterator flag together with
+// -goroutine-deriver and -detach-funcs. Loop bodies of concurrent iterators
+// go through the same SSA checks as spawned closures.
+package iteratorderive
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/example/parallel"
+	"github.com/my-example-app/ctxutil"
+	"github.com/my-example-app/telemetry/apm"
+)
+
+// ===== SHOULD REPORT =====
+
+// [BAD]: Range body neither uses ctx nor calls deriver
+//
+// Loop body runs on worker goroutines without context or deriver.
+func badRangeNoDeriver(ctx context.Context) {
+	for item := range parallel.Each([]int{1, 2, 3}) { // want `parallel.Each\(\) loop body should use context "ctx" or call goroutine deriver`
+		fmt.Println(item)
+	}
+}
+
+// [BAD]: Range body uses context before deriving
+//
+// Loop bodies are checked for ordering like spawned closures.
+func badRangeUseBeforeDerive(ctx context.Context) {
+	for item := range parallel.Each([]int{1, 2, 3}) {
+		_ = ctx.Err() // want `parallel.Each\(\) loop body uses context "ctx" before calling github.com/my-example-app/telemetry/apm.NewGoroutineContext to derive it`
+		ctx := apm.NewGoroutineContext(ctx)
+		fmt.Println(ctx, item)
+	}
+}
+
+// ===== SHOULD NOT REPORT =====
+
+// [GOOD]: Range body calls deriver
+//
+// Loop body derives its context with the deriver.
+func goodRangeCallsDeriver(ctx context.Context) {
+	for item := range parallel.Each([]int{1, 2, 3}) {
+		_ = apm.NewGoroutineContext(context.Background())
+		fmt.Println(item)
+	}
+}
+
+// [GOOD]: Range body calls deriver in IIFE
+//
+// SSA follows the deriver call into the immediately invoked closure.
+func goodRangeCallsDeriverInIIFE(ctx context.Context) {
+	for item := range parallel.Each([]int{1, 2, 3}) {
+		func() {
+			_ = apm.NewGoroutineContext(context.Background())
+		}()
+		fmt.Println(item)
+	}
+}
+
+// [GOOD]: Range body uses a detached context
+//
+// Loop body detaches from the request lifetime intentionally.
+func goodRangeDetached(ctx context.Context) {
+	for item := range parallel.Each([]int{1, 2, 3}) {
+		_ = ctxutil.Detach(context.Background())
+		fmt.Println(item)
+	}
+}
@@ -0,0 +1,172 @@
+// Package loopcancel contains test fixtures for the loopcancel checker.
+// Goroutines spawned in loops must not rely on a cancel deferred until the function returns.
+package loopcancel
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// ===== go statements =====
+
+// [BAD]: Per-iteration timeout cancelled by defer
+//
+// Every timeout stays alive until the function returns, not until its goroutine is done.
+func badDeferInLoop(ctx context.Context, items []string) {
+	for range items {
+		tctx, cancel := context.WithTimeout(ctx, time.Second)
+		defer cancel()
+		go func() { // want `goroutine uses "tctx" derived by context.WithTimeout\(\) in a loop, but its cancel is deferred until the function returns; call cancel when the goroutine or iteration is done`
+			work(tctx)
+		}()
+	}
+}
+
+// [GOOD]: Per-iteration timeout cancelled by defer
+//
+// The goroutine cancels its own timeout when it is done.
+func goodDeferInLoop(ctx context.Context, items []string) {
+	for range items {
+		tctx, cancel := context.WithTimeout(ctx, time.Second)
+		go func() {
+			defer cancel()
+			work(tctx)
+		}()
+	}
+}
+
+// [BAD]: Cancel reused across iterations
+//
+// Only the cancel of the last iteration runs, once the function returns.
+func badReusedCancel(ctx context.Context, items []string) {
+	var cancel context.CancelFunc
+	for range items {
+		var tctx context.Context
+		tctx, cancel = context.WithCancel(ctx)
+		go func() { // want `goroutine uses "tctx" derived by context.WithCancel\(\) in a loop, but its cancel is deferred until the function returns; call cancel when the goroutine or iteration is done`
+			work(tctx)
+		}()
+	}
+	defer cancel()
+}
+
+// [GOOD]: Cancel reused across iterations
+//
+// Each iteration waits for its goroutine and cancels its own context.
+func goodReusedCancel(ctx context.Context, items []string) {
+	var wg sync.WaitGroup
+	for range items {
+		tctx, cancel := context.WithCancel(ctx)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			work(tctx)
+		}()
+		wg.Wait()
+		cancel()
+	}
+}
+
+// [BAD]: Per-iteration timeout passed to go statement call
+//
+// The goroutine receives the timeout as an argument, but its cancel is still deferred.
+func badGoCall(ctx context.Context, items []string) {
+	for range items {
+		tctx, cancel := context.WithDeadline(ctx, time.Now().Add(time.Second))
+		defer cancel()
+		go work(tctx) // want `goroutine uses "tctx" derived by context.WithDeadline\(\) in a loop, but its cancel is deferred until the function returns; call cancel when the goroutine or iteration is done`
+	}
+}
+
+// [GOOD]: Per-iteration timeout passed to go statement call
+//
+// The goroutine receives the cancel and calls it when done.
+func goodGoCall(ctx context.Context, items []string) {
+	for range items {
+		tctx, cancel := context.WithDeadline(ctx, time.Now().Add(time.Second))
+		go workAndCancel(tctx, cancel)
+	}
+}
+
+// [GOOD]: Timeout derived once outside the loop
+//
+// A single timeout shared by all goroutines is cancelled once, at function exit.
+func goodDerivedOutsideLoop(ctx context.Context, items []string) {
+	tctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	for range items {
+		go func() {
+			work(tctx)
+		}()
+	}
+}
+
+// [GOOD]: Per-iteration function scope
+//
+// The deferred cancel runs at the end of each iteration's function literal.
+func goodIterationFunc(ctx context.Context, items []string) {
+	for range items {
+		func() {
+			tctx, cancel := context.WithTimeout(ctx, time.Second)
+			defer cancel()
+			var wg sync.WaitGroup
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				work(tctx)
+			}()
+			wg.Wait()
+		}()
+	}
+}
+
+// ===== spawn APIs =====
+
+// [BAD]: Per-iteration timeout in errgroup closure
+//
+// The errgroup closure uses a timeout that is only cancelled at function exit.
+func badErrgroup(ctx context.Context, items []string) error {
+	g, gctx := errgroup.WithContext(ctx)
+	for range items {
+		tctx, cancel := context.WithTimeout(gctx, time.Second)
+		defer cancel()
+		g.Go(func() error { // want `errgroup.Group.Go\(\) closure uses "tctx" derived by context.WithTimeout\(\) in a loop, but its cancel is deferred until the function returns; call cancel when the goroutine or iteration is done`
+			return workErr(tctx)
+		})
+	}
+	return g.Wait()
+}
+
+// [GOOD]: Per-iteration timeout in errgroup closure
+//
+// The errgroup closure cancels its own timeout.
+func goodErrgroup(ctx context.Context, items []string) error {
+	g, gctx := errgroup.WithContext(ctx)
+	for range items {
+		tctx, cancel := context.WithTimeout(gctx, time.Second)
+		g.Go(func() error {
+			defer cancel()
+			return workErr(tctx)
+		})
+	}
+	return g.Wait()
+}
+
+//vt:helper
+func work(ctx context.Context) {
+	_ = ctx
+}
+
+//vt:helper
+func workErr(ctx context.Context) error {
+	return ctx.Err()
+}
+
+//vt:helper
+func workAndCancel(ctx context.Context, cancel context.CancelFunc) {
+	defer cancel()
+	_ = ctx
+}
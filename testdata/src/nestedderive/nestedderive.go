@@ -0,0 +1,47 @@
+// Package nestedderive contains test fixtures for deriver requirements of nested spawns.
+// By default, the deriver must be called again at every level.
+package nestedderive
+
+import (
+	"context"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/my-example-app/telemetry/apm"
+)
+
+// [BAD]: Nested goroutine reuses outer derived context
+//
+// The nested goroutine does not call the deriver, although the outer closure did.
+func badNestedReusesDerived(ctx context.Context) error {
+	g, _ := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		ctx := apm.NewGoroutineContext(ctx)
+		go func() { // want "goroutine should call github.com/my-example-app/telemetry/apm.NewGoroutineContext to derive context"
+			work(ctx)
+		}()
+		return nil
+	})
+	return g.Wait()
+}
+
+// [GOOD]: Nested goroutine reuses outer derived context
+//
+// Every level calls the deriver.
+func goodNestedReusesDerived(ctx context.Context) error {
+	g, _ := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		ctx := apm.NewGoroutineContext(ctx)
+		go func() {
+			ctx := apm.NewGoroutineContext(ctx)
+			work(ctx)
+		}()
+		return nil
+	})
+	return g.Wait()
+}
+
+//vt:helper
+func work(ctx context.Context) {
+	_ = ctx
+}
@@ -0,0 +1,67 @@
+// Package nestedderiveonce contains test fixtures for -goroutine-deriver-once.
+// Nested spawns may reuse a context derived by an enclosing spawned closure.
+package nestedderiveonce
+
+import (
+	"context"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/my-example-app/telemetry/apm"
+)
+
+// [BAD]: Nested goroutine inherits derived context
+//
+// The context is derived before spawning, on the parent goroutine, so nothing is inherited.
+func badDerivedBeforeSpawn(ctx context.Context) {
+	dctx := apm.NewGoroutineContext(ctx)
+	go func() { // want "goroutine should call github.com/my-example-app/telemetry/apm.NewGoroutineContext to derive context"
+		work(dctx)
+	}()
+}
+
+// [GOOD]: Nested goroutine inherits derived context
+//
+// The outer errgroup closure derived the context, which the nested goroutine reuses.
+func goodInheritsDerived(ctx context.Context) error {
+	g, _ := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		ctx := apm.NewGoroutineContext(ctx)
+		go func() {
+			work(ctx)
+		}()
+		return nil
+	})
+	return g.Wait()
+}
+
+// [BAD]: Nested goroutine ignores derived context
+//
+// The nested goroutine captures only the original context, not the derived one.
+func badIgnoresDerived(ctx context.Context) {
+	go func() {
+		dctx := apm.NewGoroutineContext(ctx)
+		work(dctx)
+		go func() { // want "goroutine should call github.com/my-example-app/telemetry/apm.NewGoroutineContext to derive context"
+			work(ctx)
+		}()
+	}()
+}
+
+// [GOOD]: Nested goroutine ignores derived context
+//
+// The nested goroutine uses the context derived by the outer goroutine.
+func goodUsesDerived(ctx context.Context) {
+	go func() {
+		dctx := apm.NewGoroutineContext(ctx)
+		work(dctx)
+		go func() {
+			work(dctx)
+		}()
+	}()
+}
+
+//vt:helper
+func work(ctx context.Context) {
+	_ = ctx
+}
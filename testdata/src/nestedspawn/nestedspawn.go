@@ -0,0 +1,96 @@
+// Package nestedspawn contains test fixtures for nested spawns inheriting context scope.
+// Spawned closures inherit the enclosing context names and the contexts they capture.
+package nestedspawn
+
+import (
+	"context"
+	"sort"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// ===== Captured context scope =====
+
+// [BAD]: Nested goroutine in spawned closure capturing context
+//
+// The outer closure captures ctx, so the goroutine it spawns is checked against it.
+func badCapturedScope() {
+	ctx := context.Background()
+	go func() {
+		work(ctx)
+		go func() { // want `goroutine does not propagate context "ctx"`
+			work(context.TODO())
+		}()
+	}()
+}
+
+// [GOOD]: Nested goroutine in spawned closure capturing context
+//
+// The nested goroutine also uses the captured ctx.
+func goodCapturedScope() {
+	ctx := context.Background()
+	go func() {
+		work(ctx)
+		go func() {
+			work(ctx)
+		}()
+	}()
+}
+
+// [GOOD]: Nested goroutine in synchronous callback capturing context
+//
+// The sort.Slice comparator runs synchronously, so it does not inherit the captured ctx.
+func goodSynchronousCallbackScope(items []int) {
+	ctx := context.Background()
+	sort.Slice(items, func(i, j int) bool {
+		work(ctx)
+		go func() {
+			work(context.TODO())
+		}()
+		return items[i] < items[j]
+	})
+}
+
+// [BAD]: Nested goroutine in errgroup closure
+//
+// Multi-level fan-out is checked at every level.
+func badErrgroupFanOut(ctx context.Context) error {
+	g, gctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		go func() { // want `goroutine does not propagate context "ctx"`
+			work(context.TODO())
+		}()
+		return gctx.Err()
+	})
+	return g.Wait()
+}
+
+// [GOOD]: Nested goroutine in errgroup closure
+//
+// The nested goroutine uses the errgroup context captured by the outer closure.
+func goodErrgroupFanOut(ctx context.Context) error {
+	g, gctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		go func() {
+			work(gctx)
+		}()
+		return nil
+	})
+	return g.Wait()
+}
+
+// [GOOD]: Closure not capturing context
+//
+// Spawned closures without a captured context do not start a scope of their own.
+func goodNoCapturedContext() {
+	go func() {
+		go func() {
+			work(context.TODO())
+		}()
+	}()
+}
+
+//vt:helper
+func work(ctx context.Context) {
+	_ = ctx
+}
@@ -31,6 +31,13 @@ func runMultipleFuncs(fn1, fn2 func()) {
 	go fn2()
 }
 
+//goroutinectx:spawner //vt:helper
+func runWithCtxFunc(ctx context.Context, fn func(context.Context) error) {
+	go func() {
+		_ = fn(ctx)
+	}()
+}
+
 // ===== SHOULD REPORT =====
 
 // [BAD]: Basic errgroup func context usage
@@ -176,6 +183,26 @@ func goodFuncHasOwnCtx(ctx context.Context) {
 	_ = g
 }
 
+// [BAD]: Func argument ignores its own ctx param
+//
+// Function declares own context parameter but captures the outer context instead.
+func badFuncIgnoresOwnCtx(ctx context.Context) {
+	runWithCtxFunc(ctx, func(_ context.Context) error { // want `runWithCtxFunc\(\) func argument should use its own context parameter instead of capturing "ctx"`
+		_ = ctx
+		return nil
+	})
+}
+
+// [GOOD]: Func argument uses its own ctx param
+//
+// Function declares own context parameter and uses it.
+func goodFuncUsesOwnCtx(ctx context.Context) {
+	runWithCtxFunc(ctx, func(ctx context.Context) error {
+		_ = ctx
+		return nil
+	})
+}
+
 // ===== FACTORY FUNCTION PATTERNS =====
 
 //vt:helper
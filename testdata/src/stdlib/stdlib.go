@@ -0,0 +1,255 @@
+// Package stdlib contains test fixtures for the stdlib checker.
+// Standard library APIs that run callbacks on other goroutines or later.
+package stdlib
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/my-example-app/ctxutil"
+)
+
+type resource struct {
+	name string
+}
+
+// ===== time.AfterFunc =====
+
+// [BAD]: time.AfterFunc callback
+//
+// The callback runs on its own goroutine after the timer fires, without the context.
+func badTimeAfterFunc(ctx context.Context) {
+	time.AfterFunc(time.Second, func() { // want `time.AfterFunc\(\) callback should use context "ctx"`
+		fmt.Println("timeout")
+	})
+}
+
+// [GOOD]: time.AfterFunc callback
+//
+// The callback uses the context, so it can observe cancellation.
+func goodTimeAfterFunc(ctx context.Context) {
+	time.AfterFunc(time.Second, func() {
+		work(ctx)
+	})
+}
+
+// ===== http.Server.RegisterOnShutdown =====
+
+// [BAD]: http.Server.RegisterOnShutdown callback
+//
+// The shutdown hook runs on its own goroutine without the context.
+func badRegisterOnShutdown(ctx context.Context, srv *http.Server) {
+	srv.RegisterOnShutdown(func() { // want `http.Server.RegisterOnShutdown\(\) callback should use context "ctx"`
+		fmt.Println("shutting down")
+	})
+}
+
+// [GOOD]: http.Server.RegisterOnShutdown callback
+//
+// The shutdown hook uses the context.
+func goodRegisterOnShutdown(ctx context.Context, srv *http.Server) {
+	srv.RegisterOnShutdown(func() {
+		work(ctx)
+	})
+}
+
+// ===== context.AfterFunc =====
+
+// [BAD]: context.AfterFunc callback passes done context
+//
+// The callback runs once ctx is done, so passing ctx downstream always fails.
+func badContextAfterFunc(ctx context.Context) {
+	context.AfterFunc(ctx, func() { // want `context.AfterFunc\(\) callback passes "ctx" to work, but ctx is already done when the callback runs; detach it before registering \(e.g., context.WithoutCancel\(ctx\)\) instead`
+		work(ctx)
+	})
+}
+
+// [GOOD]: context.AfterFunc callback passes done context
+//
+// The callback passes a context detached from the cancellation of ctx before registering.
+func goodContextAfterFunc(ctx context.Context) {
+	dctx := context.WithoutCancel(ctx)
+	context.AfterFunc(ctx, func() {
+		work(dctx)
+	})
+}
+
+// [BAD]: context.AfterFunc callback passes derived done context
+//
+// A context derived from ctx is done as soon as ctx is.
+func badContextAfterFuncDerived(ctx context.Context) {
+	tctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	context.AfterFunc(ctx, func() { // want `context.AfterFunc\(\) callback passes "tctx" to work, but ctx is already done when the callback runs; detach it before registering \(e.g., context.WithoutCancel\(ctx\)\) instead`
+		work(tctx)
+	})
+}
+
+// [GOOD]: context.AfterFunc callback passes derived done context
+//
+// The timeout is derived from a detached context, so it is not done with ctx.
+func goodContextAfterFuncDerived(ctx context.Context) {
+	dctx := context.WithoutCancel(ctx)
+	tctx, cancel := context.WithTimeout(dctx, time.Second)
+	defer cancel()
+	context.AfterFunc(ctx, func() {
+		work(tctx)
+	})
+}
+
+// [BAD]: context.AfterFunc callback passes done context declared with var
+//
+// A var declaration derives from ctx just like a short variable declaration.
+func badContextAfterFuncDerivedVar(ctx context.Context) {
+	var vctx = context.WithValue(ctx, "request-id", 1)
+	context.AfterFunc(ctx, func() { // want `context.AfterFunc\(\) callback passes "vctx" to work, but ctx is already done when the callback runs; detach it before registering \(e.g., context.WithoutCancel\(ctx\)\) instead`
+		work(vctx)
+	})
+}
+
+// [BAD]: context.AfterFunc callback passes done context from multi-value assignment
+//
+// Each value of a multi-value assignment is traced separately.
+func badContextAfterFuncDerivedMultiValue(ctx context.Context) {
+	vctx, n := context.WithValue(ctx, "request-id", 1), 1
+	context.AfterFunc(ctx, func() { // want `context.AfterFunc\(\) callback passes "vctx" to work, but ctx is already done when the callback runs; detach it before registering \(e.g., context.WithoutCancel\(ctx\)\) instead`
+		work(vctx)
+		fmt.Println(n)
+	})
+}
+
+// [GOOD]: context.AfterFunc callback passes context detached before registration
+//
+// The derived context is replaced with a detached one before the callback is registered.
+func goodContextAfterFuncDetachedBeforeRegister(ctx context.Context) {
+	vctx := context.WithValue(ctx, "request-id", 1)
+	vctx = context.WithoutCancel(vctx)
+	context.AfterFunc(ctx, func() {
+		work(vctx)
+	})
+}
+
+// [BAD]: context.AfterFunc callback passes context detached after registration
+//
+// The callback may run before the derived context is replaced with a detached one.
+func badContextAfterFuncDetachedAfterRegister(ctx context.Context) {
+	vctx := context.WithValue(ctx, "request-id", 1)
+	context.AfterFunc(ctx, func() { // want `context.AfterFunc\(\) callback passes "vctx" to work, but ctx is already done when the callback runs; detach it before registering \(e.g., context.WithoutCancel\(ctx\)\) instead`
+		work(vctx)
+	})
+	vctx = context.WithoutCancel(vctx)
+	fmt.Println(vctx)
+}
+
+// [BAD]: context.AfterFunc callback derives from done context
+//
+// Deriving a timeout inside the callback still inherits the cancellation of ctx.
+func badContextAfterFuncDerivedInside(ctx context.Context) {
+	context.AfterFunc(ctx, func() { // want `context.AfterFunc\(\) callback passes "ctx" to context.WithTimeout, but ctx is already done when the callback runs; detach it before registering \(e.g., context.WithoutCancel\(ctx\)\) instead`
+		tctx, cancel := context.WithTimeout(ctx, time.Second)
+		defer cancel()
+		work(tctx)
+	})
+}
+
+// [GOOD]: context.AfterFunc callback derives from done context
+//
+// Deriving from a detached context keeps the values of ctx without its cancellation.
+func goodContextAfterFuncDerivedInside(ctx context.Context) {
+	dctx := context.WithoutCancel(ctx)
+	context.AfterFunc(ctx, func() {
+		tctx, cancel := context.WithTimeout(dctx, time.Second)
+		defer cancel()
+		work(tctx)
+	})
+}
+
+// [GOOD]: context.AfterFunc callback passes configured detached context
+//
+// Functions listed in -detach-funcs detach the context like context.WithoutCancel.
+func goodContextAfterFuncDetachFunc(ctx context.Context) {
+	dctx := ctxutil.Detach(ctx)
+	context.AfterFunc(ctx, func() {
+		work(dctx)
+	})
+}
+
+// [BAD]: context.AfterFunc callback captures registered context
+//
+// The callback runs once ctx is done, so capturing ctx only retains it.
+func badContextAfterFuncCapture(ctx context.Context) {
+	context.AfterFunc(ctx, func() { // want `context.AfterFunc\(\) callback captures "ctx", the context it is registered on; it runs once ctx is done, so capturing it only retains it`
+		fmt.Println(ctx.Err())
+	})
+}
+
+// [GOOD]: context.AfterFunc callback captures registered context
+//
+// The callback does not capture the context it is registered on.
+func goodContextAfterFuncCapture(ctx context.Context) {
+	done := make(chan struct{})
+	context.AfterFunc(ctx, func() {
+		close(done)
+	})
+}
+
+// ===== runtime.SetFinalizer =====
+
+// [BAD]: runtime.SetFinalizer callback captures context
+//
+// The finalizer runs whenever the object is collected and retains the context until then.
+func badSetFinalizer(ctx context.Context, r *resource) {
+	runtime.SetFinalizer(r, func(r *resource) { // want `runtime.SetFinalizer\(\) callback captures context "ctx", which it retains until the object is collected`
+		work(ctx)
+	})
+}
+
+// [GOOD]: runtime.SetFinalizer callback captures context
+//
+// The finalizer only uses its argument.
+func goodSetFinalizer(ctx context.Context, r *resource) {
+	runtime.SetFinalizer(r, func(r *resource) {
+		fmt.Println(r.name)
+	})
+}
+
+// ===== testing.T.Cleanup =====
+
+// [BAD]: testing.T.Cleanup callback captures context
+//
+// The test context is cancelled before cleanup functions run.
+func badCleanup(ctx context.Context, t *testing.T) {
+	t.Cleanup(func() { // want `testing.T.Cleanup\(\) callback captures context "ctx", which may be cancelled before cleanup runs \(t.Context\(\) is\)`
+		work(ctx)
+	})
+}
+
+// [GOOD]: testing.T.Cleanup callback captures context
+//
+// Cleanup uses a fresh context that is not cancelled.
+func goodCleanup(ctx context.Context, t *testing.T) {
+	t.Cleanup(func() {
+		work(context.Background())
+	})
+}
+
+// ===== sync.Once.Do =====
+
+// [GOOD]: sync.Once.Do callback runs synchronously
+//
+// Once.Do runs the callback on the calling goroutine, so it is not a spawn.
+func goodOnceDo(ctx context.Context, once *sync.Once) {
+	once.Do(func() {
+		fmt.Println("init")
+	})
+}
+
+//vt:helper
+func work(ctx context.Context) {
+	_ = ctx
+}